@@ -0,0 +1,375 @@
+package flex
+
+// blockMargin is a set of adjoining vertical margins that collapse into one.
+// The collapsed margin is the largest positive margin plus the most negative
+// margin
+type blockMargin struct {
+	positive float32
+	negative float32
+}
+
+func newBlockMargin(margin float32) blockMargin {
+	if margin > 0 {
+		return blockMargin{positive: margin}
+	}
+	return blockMargin{negative: margin}
+}
+
+// add collapses m with other
+func (m blockMargin) add(other blockMargin) blockMargin {
+	return blockMargin{
+		positive: fmaxf(m.positive, other.positive),
+		negative: fminf(m.negative, other.negative),
+	}
+}
+
+// size returns size of the collapsed margin
+func (m blockMargin) size() float32 {
+	return m.positive + m.negative
+}
+
+// nodeLayoutParent returns the parent that lays out node, skipping
+// display: contents parents
+func nodeLayoutParent(node *Node) *Node {
+	parent := node.Parent
+	for parent != nil && parent.Style.Display == DisplayContents {
+		parent = parent.Parent
+	}
+	return parent
+}
+
+// nodeBlockCanCollapseMargin returns true if margins of children of node
+// collapse through top or bottom edge of node with margins of its siblings
+func nodeBlockCanCollapseMargin(node *Node, edge Edge, parentWidth float32) bool {
+	parent := nodeLayoutParent(node)
+	if node.Style.Display != DisplayBlock || parent == nil ||
+		(parent.Style.Display != DisplayBlock && parent.Style.Display != DisplayInlineBlock) ||
+		node.Style.Overflow != OverflowVisible ||
+		node.Style.PositionType == PositionTypeAbsolute {
+		return false
+	}
+	if edge == EdgeTop {
+		return nodeLeadingPaddingAndBorder(node, FlexDirectionColumn, parentWidth) == 0
+	}
+	height := node.Style.Dimensions[DimensionHeight].Unit
+	minHeight := node.Style.MinDimensions[DimensionHeight].Unit
+	return nodeTrailingPaddingAndBorder(node, FlexDirectionColumn, parentWidth) == 0 &&
+		(height == UnitAuto || height == UnitUndefined) &&
+		(minHeight == UnitAuto || minHeight == UnitUndefined)
+}
+
+// nodeBlockIsEmpty returns true if margins collapse through child, i.e. it's
+// an empty block without height, padding or border
+func nodeBlockIsEmpty(child *Node, innerWidth float32) bool {
+	return child.Style.Display == DisplayBlock &&
+		child.Measure == nil &&
+		child.Layout.measuredDimensions[DimensionHeight] == 0 &&
+		nodePaddingAndBorderForAxis(child, FlexDirectionColumn, innerWidth) == 0
+}
+
+// nodeBlockMaxContentWidth returns the width of content of node if none of
+// its lines wrap
+func nodeBlockMaxContentWidth(children []*Node, innerHeight float32, direction Direction, config *Config, ctx *layoutContext) float32 {
+	var width, lineWidth float32
+	for _, child := range children {
+		if child.Style.Display == DisplayNone || child.Style.PositionType == PositionTypeAbsolute {
+			continue
+		}
+		childWidth := nodeMeasureChild(child, FlexDirectionRow, Undefined, false,
+			Undefined, innerHeight, direction, "block-measure", config, ctx)
+		if child.Style.Display == DisplayInlineBlock {
+			lineWidth += childWidth
+			width = fmaxf(width, lineWidth)
+			continue
+		}
+		lineWidth = 0
+		width = fmaxf(width, childWidth)
+	}
+	return width
+}
+
+// nodeBlockLayoutImpl lays out children of node one after another like CSS
+// block flow. Vertical margins of adjoining children collapse, and
+// display: inline-block children are placed in lines aligned on their baseline
+func nodeBlockLayoutImpl(node *Node, children []*Node, availableWidth float32, availableHeight float32,
+	direction Direction, widthMeasureMode MeasureMode, heightMeasureMode MeasureMode,
+	parentWidth float32, parentHeight float32, performLayout bool, config *Config, ctx *layoutContext) {
+	node.Layout.HadOverflow = false
+
+	rowAxis := resolveFlexDirection(FlexDirectionRow, direction)
+	paddingAndBorderAxisRow := nodePaddingAndBorderForAxis(node, FlexDirectionRow, parentWidth)
+	paddingAndBorderAxisColumn := nodePaddingAndBorderForAxis(node, FlexDirectionColumn, parentWidth)
+	marginAxisRow := nodeMarginForAxis(node, FlexDirectionRow, parentWidth)
+	marginAxisColumn := nodeMarginForAxis(node, FlexDirectionColumn, parentWidth)
+
+	definiteInnerHeight := Undefined
+	if heightMeasureMode == MeasureModeExactly {
+		definiteInnerHeight = nodeBoundAxis(node, FlexDirectionColumn, availableHeight-marginAxisColumn, parentHeight, parentWidth) -
+			paddingAndBorderAxisColumn
+	}
+
+	var absoluteChildren []*Node
+	var flowChildren []*Node
+	for _, child := range children {
+		if child.Style.Display == DisplayNone {
+			zeroOutLayoutRecursivly(child)
+			nodeSetHasNewLayout(child)
+			child.IsDirty = false
+			continue
+		}
+		resolveDimensions(child)
+		if child.Style.PositionType == PositionTypeAbsolute {
+			absoluteChildren = append(absoluteChildren, child)
+			continue
+		}
+		flowChildren = append(flowChildren, child)
+	}
+
+	// STEP 1: SIZING WIDTH
+	// Block fills available width, otherwise it shrinks to fit its content
+	var contentWidth float32
+	if widthMeasureMode != MeasureModeExactly {
+		contentWidth = nodeBlockMaxContentWidth(flowChildren, definiteInnerHeight, direction, config, ctx)
+	}
+	width := nodeSizeForContent(node, FlexDirectionRow, contentWidth,
+		availableWidth-marginAxisRow, widthMeasureMode, parentWidth, parentWidth)
+	innerWidth := width - paddingAndBorderAxisRow
+
+	node.Layout.measuredDimensions[DimensionWidth] = width
+
+	// STEP 2: LAYING OUT CHILDREN IN FLOW
+	canCollapseTop := nodeBlockCanCollapseMargin(node, EdgeTop, parentWidth)
+	canCollapseBottom := nodeBlockCanCollapseMargin(node, EdgeBottom, parentWidth)
+	var collapsedMargins [2]blockMargin
+	var pending blockMargin
+	hasContent := false
+	offset := float32(0)
+	lineIndex := 0
+	positions := make([]float32, 2*len(flowChildren))
+
+	// placeContent adds margins pending above content at offset
+	placeContent := func() {
+		if !hasContent && canCollapseTop {
+			collapsedMargins[0] = pending
+		} else {
+			offset += pending.size()
+		}
+		pending = blockMargin{}
+		hasContent = true
+	}
+
+	for i := 0; i < len(flowChildren); {
+		child := flowChildren[i]
+		if child.Style.Display == DisplayInlineBlock {
+			end := i
+			for end < len(flowChildren) && flowChildren[end].Style.Display == DisplayInlineBlock {
+				end++
+			}
+			placeContent()
+			offset += nodeBlockLayoutLine(flowChildren[i:end], positions[2*i:2*end], offset, innerWidth,
+				definiteInnerHeight, direction, &lineIndex, performLayout, config, ctx)
+			i = end
+			continue
+		}
+
+		marginRow := nodeMarginForAxis(child, FlexDirectionRow, innerWidth)
+		childWidth := innerWidth
+		if nodeIsStyleDimDefined(child, FlexDirectionRow, innerWidth) {
+			childWidth = resolveValue(child.resolvedDimensions[DimensionWidth], innerWidth) + marginRow
+		}
+		childHeight, childHeightMeasureMode := Undefined, MeasureModeUndefined
+		if nodeIsStyleDimDefined(child, FlexDirectionColumn, definiteInnerHeight) {
+			childHeight = resolveValue(child.resolvedDimensions[DimensionHeight], definiteInnerHeight) +
+				nodeMarginForAxis(child, FlexDirectionColumn, innerWidth)
+			childHeightMeasureMode = MeasureModeExactly
+		} else {
+			constrainMaxSizeForMode(child, FlexDirectionColumn, definiteInnerHeight, innerWidth, &childHeightMeasureMode, &childHeight)
+		}
+
+		layoutNodeInternal(child,
+			childWidth,
+			childHeight,
+			direction,
+			MeasureModeExactly,
+			childHeightMeasureMode,
+			innerWidth,
+			definiteInnerHeight,
+			performLayout,
+			"block",
+			config,
+			ctx)
+		child.lineIndex = lineIndex
+		lineIndex++
+
+		// Auto margins of a block with width center it or push it to the end
+		left := nodeLeadingMargin(child, rowAxis, innerWidth)
+		free := innerWidth - child.Layout.measuredDimensions[DimensionWidth] - marginRow
+		if free > 0 && marginLeadingValue(child, rowAxis).Unit == UnitAuto {
+			if marginTrailingValue(child, rowAxis).Unit == UnitAuto {
+				left += free / 2
+			} else {
+				left += free
+			}
+		}
+		positions[2*i] = left
+
+		top := newBlockMargin(nodeLeadingMargin(child, FlexDirectionColumn, innerWidth))
+		bottom := newBlockMargin(nodeTrailingMargin(child, FlexDirectionColumn, innerWidth))
+		if child.Style.Display == DisplayBlock {
+			top = top.add(child.Layout.collapsedMargins[0])
+			bottom = bottom.add(child.Layout.collapsedMargins[1])
+		}
+		if nodeBlockIsEmpty(child, innerWidth) {
+			// Margins of an empty block collapse through it
+			pending = pending.add(top).add(bottom)
+			positions[2*i+1] = offset + pending.size()
+			i++
+			continue
+		}
+		pending = pending.add(top)
+		placeContent()
+		positions[2*i+1] = offset
+		offset += child.Layout.measuredDimensions[DimensionHeight]
+		pending = bottom
+		i++
+	}
+
+	if !hasContent && canCollapseTop {
+		collapsedMargins[0] = pending
+	} else if canCollapseBottom {
+		collapsedMargins[1] = pending
+	} else {
+		offset += pending.size()
+	}
+	node.Layout.collapsedMargins = collapsedMargins
+
+	height := nodeSizeForContent(node, FlexDirectionColumn, offset,
+		availableHeight-marginAxisColumn, heightMeasureMode, parentHeight, parentWidth)
+	innerHeight := height - paddingAndBorderAxisColumn
+
+	node.Layout.measuredDimensions[DimensionHeight] = height
+
+	if !performLayout {
+		return
+	}
+
+	// STEP 3: POSITIONING CHILDREN
+	leadingPaddingAndBorderRow := nodeLeadingPaddingAndBorder(node, rowAxis, parentWidth)
+	leadingPaddingAndBorderColumn := nodeLeadingPaddingAndBorder(node, FlexDirectionColumn, parentWidth)
+	for i, child := range flowChildren {
+		child.Layout.Position[pos[rowAxis]] = leadingPaddingAndBorderRow + positions[2*i] +
+			nodeRelativePosition(child, rowAxis, innerWidth)
+		child.Layout.Position[EdgeTop] = leadingPaddingAndBorderColumn + positions[2*i+1] +
+			nodeRelativePosition(child, FlexDirectionColumn, innerHeight)
+		if rowAxis == FlexDirectionRowReverse {
+			nodeSetChildTrailingPosition(node, child, rowAxis)
+		}
+	}
+
+	// STEP 4: SIZING AND POSITIONING ABSOLUTE CHILDREN
+	if nodeIsContainingBlock(node) {
+		for _, child := range absoluteChildren {
+			nodeAbsoluteSizeChild(node, child, innerWidth, MeasureModeExactly, innerHeight, direction, config, ctx)
+			nodeAbsolutePositionDescendant(node, node, child, 0, 0, innerWidth, innerHeight, direction)
+		}
+		for _, child := range flowChildren {
+			if child.Style.PositionType != PositionTypeStatic {
+				continue
+			}
+			nodeAbsoluteLayoutDescendants(node,
+				child,
+				child.Layout.Position[EdgeLeft],
+				child.Layout.Position[EdgeTop],
+				innerWidth,
+				MeasureModeExactly,
+				innerHeight,
+				direction,
+				config,
+				ctx)
+		}
+	}
+}
+
+// nodeBaselineNeedsLayout returns true if Baseline of node uses positions of
+// its children, which are only set when node is laid out, not measured
+func nodeBaselineNeedsLayout(node *Node) bool {
+	return node.Baseline == nil && (len(node.Children) > 0 || node.virtualList != nil)
+}
+
+// nodeBlockLayoutLine lays out a run of inline-block children in lines that
+// wrap at innerWidth. It stores left and top of each child in positions and
+// returns height of the lines
+func nodeBlockLayoutLine(children []*Node, positions []float32, offset float32, innerWidth float32, innerHeight float32,
+	direction Direction, lineIndex *int, performLayout bool, config *Config, ctx *layoutContext) float32 {
+	rowAxis := resolveFlexDirection(FlexDirectionRow, direction)
+	for _, child := range children {
+		marginRow := nodeMarginForAxis(child, FlexDirectionRow, innerWidth)
+		childWidth, childWidthMeasureMode := innerWidth, MeasureModeAtMost
+		if nodeIsStyleDimDefined(child, FlexDirectionRow, innerWidth) {
+			childWidth = resolveValue(child.resolvedDimensions[DimensionWidth], innerWidth) + marginRow
+			childWidthMeasureMode = MeasureModeExactly
+		} else if FloatIsUndefined(innerWidth) {
+			childWidthMeasureMode = MeasureModeUndefined
+		}
+		childHeight, childHeightMeasureMode := Undefined, MeasureModeUndefined
+		if nodeIsStyleDimDefined(child, FlexDirectionColumn, innerHeight) {
+			childHeight = resolveValue(child.resolvedDimensions[DimensionHeight], innerHeight) +
+				nodeMarginForAxis(child, FlexDirectionColumn, innerWidth)
+			childHeightMeasureMode = MeasureModeExactly
+		} else {
+			constrainMaxSizeForMode(child, FlexDirectionColumn, innerHeight, innerWidth, &childHeightMeasureMode, &childHeight)
+		}
+
+		// Baseline of a child without a baseline function needs positions of
+		// its children, so it's laid out even when only size of node is
+		// requested
+		layoutNodeInternal(child,
+			childWidth,
+			childHeight,
+			direction,
+			childWidthMeasureMode,
+			childHeightMeasureMode,
+			innerWidth,
+			innerHeight,
+			performLayout || nodeBaselineNeedsLayout(child),
+			"block",
+			config,
+			ctx)
+	}
+
+	height := float32(0)
+	for start := 0; start < len(children); {
+		// Collect children that fit in the line, there's at least one
+		end := start
+		lineWidth := float32(0)
+		for ; end < len(children); end++ {
+			child := children[end]
+			childWidth := child.Layout.measuredDimensions[DimensionWidth] + nodeMarginForAxis(child, FlexDirectionRow, innerWidth)
+			if end > start && lineWidth+childWidth > innerWidth {
+				break
+			}
+			lineWidth += childWidth
+		}
+
+		// Align children of the line on their baseline
+		var ascent, descent float32
+		for _, child := range children[start:end] {
+			baseline := nodeLeadingMargin(child, FlexDirectionColumn, innerWidth) + Baseline(child)
+			ascent = fmaxf(ascent, baseline)
+			descent = fmaxf(descent, child.Layout.measuredDimensions[DimensionHeight]+
+				nodeMarginForAxis(child, FlexDirectionColumn, innerWidth)-baseline)
+		}
+
+		left := float32(0)
+		for i, child := range children[start:end] {
+			child.lineIndex = *lineIndex
+			positions[2*(start+i)] = left + nodeLeadingMargin(child, rowAxis, innerWidth)
+			positions[2*(start+i)+1] = offset + height + ascent - Baseline(child)
+			left += child.Layout.measuredDimensions[DimensionWidth] + nodeMarginForAxis(child, FlexDirectionRow, innerWidth)
+		}
+		*lineIndex++
+		height += ascent + descent
+		start = end
+	}
+	return height
+}
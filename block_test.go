@@ -0,0 +1,341 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertLayout(t *testing.T, node *Node, left, top, width, height float32) {
+	t.Helper()
+	assertFloatEqual(t, left, node.LayoutGetLeft())
+	assertFloatEqual(t, top, node.LayoutGetTop())
+	assertFloatEqual(t, width, node.LayoutGetWidth())
+	assertFloatEqual(t, height, node.LayoutGetHeight())
+}
+
+func TestBlock_sibling_margins_collapse(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayBlock)
+	root.StyleSetWidth(100)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetMargin(EdgeTop, 15)
+	root_child0.StyleSetMargin(EdgeBottom, 20)
+	root_child0.StyleSetHeight(10)
+	root.InsertChild(root_child0, 0)
+
+	root_child1 := NewNodeWithConfig(config)
+	root_child1.StyleSetMargin(EdgeTop, 30)
+	root_child1.StyleSetMargin(EdgeBottom, 10)
+	root_child1.StyleSetHeight(10)
+	root.InsertChild(root_child1, 1)
+
+	root_child2 := NewNodeWithConfig(config)
+	root_child2.StyleSetMargin(EdgeTop, -5)
+	root_child2.StyleSetHeight(10)
+	root.InsertChild(root_child2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 100, 80)
+	assertLayout(t, root_child0, 0, 15, 100, 10)
+	assertLayout(t, root_child1, 0, 55, 100, 10)
+	assertLayout(t, root_child2, 0, 70, 100, 10)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertLayout(t, root, 0, 0, 100, 80)
+	assertLayout(t, root_child0, 0, 15, 100, 10)
+	assertLayout(t, root_child1, 0, 55, 100, 10)
+	assertLayout(t, root_child2, 0, 70, 100, 10)
+}
+
+func TestBlock_parent_and_child_margins_collapse(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayBlock)
+	root.StyleSetWidth(100)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetDisplay(DisplayBlock)
+	root_child0.StyleSetMargin(EdgeVertical, 10)
+	root.InsertChild(root_child0, 0)
+
+	root_child0_child0 := NewNodeWithConfig(config)
+	root_child0_child0.StyleSetMargin(EdgeTop, 30)
+	root_child0_child0.StyleSetMargin(EdgeBottom, 5)
+	root_child0_child0.StyleSetHeight(20)
+	root_child0.InsertChild(root_child0_child0, 0)
+
+	root_child1 := NewNodeWithConfig(config)
+	root_child1.StyleSetHeight(10)
+	root.InsertChild(root_child1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 100, 70)
+	assertLayout(t, root_child0, 0, 30, 100, 20)
+	assertLayout(t, root_child0_child0, 0, 0, 100, 20)
+	assertLayout(t, root_child1, 0, 60, 100, 10)
+
+	// padding separates margins of parent and its first child
+	root_child0.StyleSetPadding(EdgeTop, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 100, 81)
+	assertLayout(t, root_child0, 0, 10, 100, 51)
+	assertLayout(t, root_child0_child0, 0, 31, 100, 20)
+	assertLayout(t, root_child1, 0, 71, 100, 10)
+
+	// margins don't collapse through a flex container
+	root_child0.StyleSetPadding(EdgeTop, 0)
+	root.StyleSetDisplay(DisplayFlex)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 100, 85)
+	assertLayout(t, root_child0, 0, 10, 100, 55)
+	assertLayout(t, root_child0_child0, 0, 30, 100, 20)
+	assertLayout(t, root_child1, 0, 75, 100, 10)
+}
+
+func TestBlock_empty_block_margins_collapse_through(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayBlock)
+	root.StyleSetWidth(100)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetMargin(EdgeBottom, 10)
+	root_child0.StyleSetHeight(10)
+	root.InsertChild(root_child0, 0)
+
+	root_child1 := NewNodeWithConfig(config)
+	root_child1.StyleSetDisplay(DisplayBlock)
+	root_child1.StyleSetMargin(EdgeTop, 20)
+	root_child1.StyleSetMargin(EdgeBottom, 5)
+	root.InsertChild(root_child1, 1)
+
+	root_child2 := NewNodeWithConfig(config)
+	root_child2.StyleSetMargin(EdgeTop, 15)
+	root_child2.StyleSetHeight(10)
+	root.InsertChild(root_child2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 100, 40)
+	assertLayout(t, root_child0, 0, 0, 100, 10)
+	assertFloatEqual(t, 0, root_child1.LayoutGetHeight())
+	assertLayout(t, root_child2, 0, 30, 100, 10)
+}
+
+func TestBlock_auto_margins(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayBlock)
+	root.StyleSetWidth(100)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetMarginAuto(EdgeLeft)
+	root_child0.StyleSetMarginAuto(EdgeRight)
+	root_child0.StyleSetWidth(40)
+	root_child0.StyleSetHeight(10)
+	root.InsertChild(root_child0, 0)
+
+	root_child1 := NewNodeWithConfig(config)
+	root_child1.StyleSetMarginAuto(EdgeLeft)
+	root_child1.StyleSetWidth(40)
+	root_child1.StyleSetHeight(10)
+	root.InsertChild(root_child1, 1)
+
+	root_child2 := NewNodeWithConfig(config)
+	root_child2.StyleSetWidth(40)
+	root_child2.StyleSetHeight(10)
+	root.InsertChild(root_child2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 100, 30)
+	assertLayout(t, root_child0, 30, 0, 40, 10)
+	assertLayout(t, root_child1, 60, 10, 40, 10)
+	assertLayout(t, root_child2, 0, 20, 40, 10)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertLayout(t, root, 0, 0, 100, 30)
+	assertLayout(t, root_child0, 30, 0, 40, 10)
+	assertLayout(t, root_child1, 60, 10, 40, 10)
+	assertLayout(t, root_child2, 60, 20, 40, 10)
+}
+
+func TestBlock_inline_block_lines(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayBlock)
+	root.StyleSetWidth(100)
+
+	var children []*Node
+	for i, height := range []float32{20, 30, 10} {
+		child := NewNodeWithConfig(config)
+		child.StyleSetDisplay(DisplayInlineBlock)
+		child.StyleSetWidth(40)
+		child.StyleSetHeight(height)
+		root.InsertChild(child, i)
+		children = append(children, child)
+	}
+
+	root_child3 := NewNodeWithConfig(config)
+	root_child3.StyleSetMargin(EdgeTop, 5)
+	root_child3.StyleSetHeight(10)
+	root.InsertChild(root_child3, 3)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 100, 55)
+	assertLayout(t, children[0], 0, 10, 40, 20)
+	assertLayout(t, children[1], 40, 0, 40, 30)
+	assertLayout(t, children[2], 0, 30, 40, 10)
+	assertLayout(t, root_child3, 0, 45, 100, 10)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertLayout(t, root, 0, 0, 100, 55)
+	assertLayout(t, children[0], 60, 10, 40, 20)
+	assertLayout(t, children[1], 20, 0, 40, 30)
+	assertLayout(t, children[2], 60, 30, 40, 10)
+	assertLayout(t, root_child3, 0, 45, 100, 10)
+}
+
+func TestBlock_inline_block_shrinks_to_content(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayBlock)
+	root.StyleSetWidth(200)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetDisplay(DisplayInlineBlock)
+	root_child0.StyleSetPadding(EdgeAll, 5)
+	root.InsertChild(root_child0, 0)
+
+	root_child0_child0 := NewNodeWithConfig(config)
+	root_child0_child0.SetMeasureFunc(_simulate_wrapping_text)
+	root_child0.InsertChild(root_child0_child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 200, 26)
+	assertLayout(t, root_child0, 0, 0, 78, 26)
+	assertLayout(t, root_child0_child0, 5, 5, 68, 16)
+
+	root.StyleSetWidth(60)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 60, 42)
+	assertLayout(t, root_child0, 0, 0, 60, 42)
+	assertLayout(t, root_child0_child0, 5, 5, 50, 32)
+}
+
+func TestBlock_inline_block_measured_without_layout(t *testing.T) {
+	config := NewConfig()
+	performLayouts := map[*Node][]bool{}
+	config.Tracer = LayoutTracerFunc(func(event LayoutEvent) {
+		if event.Type != LayoutEventExit && event.Reason == "block" {
+			performLayouts[event.Node] = append(performLayouts[event.Node], event.PerformLayout)
+		}
+	})
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetAlignItems(AlignFlexStart)
+	root.StyleSetWidth(200)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetDisplay(DisplayBlock)
+	root.InsertChild(root_child0, 0)
+
+	root_child0_child0 := NewNodeWithConfig(config)
+	root_child0_child0.StyleSetDisplay(DisplayInlineBlock)
+	root_child0_child0.SetMeasureFunc(_simulate_wrapping_text)
+	root_child0.InsertChild(root_child0_child0, 0)
+
+	root_child0_child1 := NewNodeWithConfig(config)
+	root_child0_child1.StyleSetDisplay(DisplayInlineBlock)
+	root_child0.InsertChild(root_child0_child1, 1)
+
+	root_child0_child1_child0 := NewNodeWithConfig(config)
+	root_child0_child1_child0.StyleSetWidth(10)
+	root_child0_child1_child0.StyleSetHeight(10)
+	root_child0_child1.InsertChild(root_child0_child1_child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// the leaf is only measured while its parent is measured
+	assert.Equal(t, []bool{false, true}, performLayouts[root_child0_child0])
+	// baseline of the other child needs position of its child
+	assert.Equal(t, []bool{true, true}, performLayouts[root_child0_child1])
+	assertLayout(t, root_child0, 0, 0, 78, 16)
+	assertLayout(t, root_child0_child1, 68, 6, 10, 10)
+}
+
+func TestBlock_measure_leaf(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayBlock)
+	root.StyleSetPadding(EdgeAll, 10)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.SetMeasureFunc(_simulate_wrapping_text)
+	root.InsertChild(root_child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 88, 36)
+	assertLayout(t, root_child0, 10, 10, 68, 16)
+
+	CalculateLayout(root, 60, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 60, 52)
+	assertLayout(t, root_child0, 10, 10, 40, 32)
+}
+
+func TestBlock_in_flex_container(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(100)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetDisplay(DisplayBlock)
+	root_child0.StyleSetFlexGrow(1)
+	root.InsertChild(root_child0, 0)
+
+	root_child0_child0 := NewNodeWithConfig(config)
+	root_child0_child0.StyleSetMargin(EdgeTop, 10)
+	root_child0_child0.StyleSetHeight(20)
+	root_child0.InsertChild(root_child0_child0, 0)
+
+	root_child0_child1 := NewNodeWithConfig(config)
+	root_child0_child1.StyleSetPositionType(PositionTypeAbsolute)
+	root_child0_child1.StyleSetPosition(EdgeRight, 0)
+	root_child0_child1.StyleSetPosition(EdgeBottom, 0)
+	root_child0_child1.StyleSetWidth(10)
+	root_child0_child1.StyleSetHeight(10)
+	root_child0.InsertChild(root_child0_child1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root_child0, 0, 0, 200, 100)
+	assertLayout(t, root_child0_child0, 0, 10, 200, 20)
+	assertLayout(t, root_child0_child1, 190, 90, 10, 10)
+}
+
+func TestBlock_display_parse(t *testing.T) {
+	display, err := ParseDisplay("block")
+	assert.NoError(t, err)
+	assert.Equal(t, DisplayBlock, display)
+	display, err = ParseDisplay("inline-block")
+	assert.NoError(t, err)
+	assert.Equal(t, DisplayInlineBlock, display)
+	assert.Equal(t, "inline-block", DisplayToString(DisplayInlineBlock))
+}
@@ -0,0 +1,258 @@
+package flex
+
+// Option sets a property of a node created with New. Enum values like
+// FlexDirectionRow, JustifyCenter, WrapWrap or DisplayGrid are options
+// that set the matching style property
+type Option interface {
+	applyTo(node *Node)
+}
+
+// OptionFunc is a function used as Option
+type OptionFunc func(node *Node)
+
+func (f OptionFunc) applyTo(node *Node) {
+	f(node)
+}
+
+// Shorthands for flex direction options
+const (
+	Row           = FlexDirectionRow
+	RowReverse    = FlexDirectionRowReverse
+	Column        = FlexDirectionColumn
+	ColumnReverse = FlexDirectionColumnReverse
+)
+
+func (d Direction) applyTo(node *Node)     { node.StyleSetDirection(d) }
+func (d FlexDirection) applyTo(node *Node) { node.StyleSetFlexDirection(d) }
+func (j Justify) applyTo(node *Node)       { node.StyleSetJustifyContent(j) }
+func (w Wrap) applyTo(node *Node)          { node.StyleSetFlexWrap(w) }
+func (o Overflow) applyTo(node *Node)      { node.StyleSetOverflow(o) }
+func (d Display) applyTo(node *Node)       { node.StyleSetDisplay(d) }
+func (p PositionType) applyTo(node *Node)  { node.StyleSetPositionType(p) }
+
+// New creates a node with default config and applies opts to it in order
+func New(opts ...Option) *Node {
+	return NewNode().Apply(opts...)
+}
+
+// NewWithConfig creates a node with config and applies opts to it in order
+func NewWithConfig(config *Config, opts ...Option) *Node {
+	return NewNodeWithConfig(config).Apply(opts...)
+}
+
+// Apply applies opts to node in order and returns node
+func (node *Node) Apply(opts ...Option) *Node {
+	for _, opt := range opts {
+		opt.applyTo(node)
+	}
+	return node
+}
+
+// Children appends children to a node
+func Children(children ...*Node) Option {
+	return OptionFunc(func(node *Node) {
+		for _, child := range children {
+			node.InsertChild(child, len(node.Children))
+		}
+	})
+}
+
+// Measure sets measure function
+func Measure(measureFunc MeasureFunc) Option {
+	return OptionFunc(func(node *Node) { node.SetMeasureFunc(measureFunc) })
+}
+
+// Context sets context of a node
+func Context(context interface{}) Option {
+	return OptionFunc(func(node *Node) { node.Context = context })
+}
+
+// AlignItems sets align items
+func AlignItems(align Align) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetAlignItems(align) })
+}
+
+// AlignSelf sets align self
+func AlignSelf(align Align) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetAlignSelf(align) })
+}
+
+// AlignContent sets align content
+func AlignContent(align Align) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetAlignContent(align) })
+}
+
+// Flex sets flex
+func Flex(flex float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetFlex(flex) })
+}
+
+// FlexGrow sets flex grow
+func FlexGrow(flexGrow float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetFlexGrow(flexGrow) })
+}
+
+// FlexShrink sets flex shrink
+func FlexShrink(flexShrink float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetFlexShrink(flexShrink) })
+}
+
+// FlexBasis sets flex basis
+func FlexBasis(flexBasis float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetFlexBasis(flexBasis) })
+}
+
+// FlexBasisPercent sets flex basis percent
+func FlexBasisPercent(flexBasis float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetFlexBasisPercent(flexBasis) })
+}
+
+// FlexBasisAuto sets flex basis auto
+func FlexBasisAuto() Option {
+	return OptionFunc(NodeStyleSetFlexBasisAuto)
+}
+
+// Position sets position
+func Position(edge Edge, position float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetPosition(edge, position) })
+}
+
+// PositionPercent sets position percent
+func PositionPercent(edge Edge, position float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetPositionPercent(edge, position) })
+}
+
+// Margin sets margin
+func Margin(edge Edge, margin float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMargin(edge, margin) })
+}
+
+// MarginPercent sets margin percent
+func MarginPercent(edge Edge, margin float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMarginPercent(edge, margin) })
+}
+
+// MarginAuto sets margin auto
+func MarginAuto(edge Edge) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMarginAuto(edge) })
+}
+
+// Padding sets padding
+func Padding(edge Edge, padding float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetPadding(edge, padding) })
+}
+
+// PaddingPercent sets padding percent
+func PaddingPercent(edge Edge, padding float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetPaddingPercent(edge, padding) })
+}
+
+// Border sets border
+func Border(edge Edge, border float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetBorder(edge, border) })
+}
+
+// Gap sets gap
+func Gap(gutter Gutter, gap float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetGap(gutter, gap) })
+}
+
+// GapPercent sets gap percent
+func GapPercent(gutter Gutter, gap float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetGapPercent(gutter, gap) })
+}
+
+// Width sets width
+func Width(width float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetWidth(width) })
+}
+
+// WidthPercent sets width percent
+func WidthPercent(width float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetWidthPercent(width) })
+}
+
+// WidthAuto sets width auto
+func WidthAuto() Option {
+	return OptionFunc(func(node *Node) { node.StyleSetWidthAuto() })
+}
+
+// Height sets height
+func Height(height float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetHeight(height) })
+}
+
+// HeightPercent sets height percent
+func HeightPercent(height float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetHeightPercent(height) })
+}
+
+// HeightAuto sets height auto
+func HeightAuto() Option {
+	return OptionFunc(func(node *Node) { node.StyleSetHeightAuto() })
+}
+
+// MinWidth sets min width
+func MinWidth(minWidth float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMinWidth(minWidth) })
+}
+
+// MinWidthPercent sets min width percent
+func MinWidthPercent(minWidth float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMinWidthPercent(minWidth) })
+}
+
+// MinHeight sets min height
+func MinHeight(minHeight float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMinHeight(minHeight) })
+}
+
+// MinHeightPercent sets min height percent
+func MinHeightPercent(minHeight float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMinHeightPercent(minHeight) })
+}
+
+// MaxWidth sets max width
+func MaxWidth(maxWidth float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMaxWidth(maxWidth) })
+}
+
+// MaxWidthPercent sets max width percent
+func MaxWidthPercent(maxWidth float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMaxWidthPercent(maxWidth) })
+}
+
+// MaxHeight sets max height
+func MaxHeight(maxHeight float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMaxHeight(maxHeight) })
+}
+
+// MaxHeightPercent sets max height percent
+func MaxHeightPercent(maxHeight float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetMaxHeightPercent(maxHeight) })
+}
+
+// AspectRatio sets aspect ratio
+func AspectRatio(aspectRatio float32) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetAspectRatio(aspectRatio) })
+}
+
+// GridTemplateColumns sets grid template columns
+func GridTemplateColumns(tracks ...GridTrack) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetGridTemplateColumns(tracks...) })
+}
+
+// GridTemplateRows sets grid template rows
+func GridTemplateRows(tracks ...GridTrack) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetGridTemplateRows(tracks...) })
+}
+
+// GridColumn sets grid column
+func GridColumn(placement GridPlacement) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetGridColumn(placement) })
+}
+
+// GridRow sets grid row
+func GridRow(placement GridPlacement) Option {
+	return OptionFunc(func(node *Node) { node.StyleSetGridRow(placement) })
+}
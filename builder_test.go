@@ -0,0 +1,91 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_tree(t *testing.T) {
+	header := New(Width(50), AlignSelf(AlignFlexStart), Height(20))
+	body := New(FlexGrow(1), Margin(EdgeLeft, 10), Context("body"))
+	root := New(Row, Padding(EdgeAll, 8), Width(200), Height(100), JustifyCenter,
+		Children(header, body),
+	)
+	assert.Equal(t, FlexDirectionRow, root.Style.FlexDirection)
+	assert.Equal(t, JustifyCenter, root.Style.JustifyContent)
+	assert.Equal(t, 2, len(root.Children))
+	assert.Same(t, root, header.Parent)
+	assert.Equal(t, "body", body.Context)
+	assert.True(t, root.IsDirty)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root, 0, 0, 200, 100)
+	assertLayout(t, header, 8, 8, 50, 20)
+	assertLayout(t, body, 68, 8, 124, 84)
+}
+
+func TestBuilder_matches_setters(t *testing.T) {
+	built := New(
+		ColumnReverse, DirectionRTL, WrapWrap, OverflowHidden, DisplayFlex, PositionTypeAbsolute,
+		AlignItems(AlignCenter), AlignContent(AlignSpaceAround),
+		Flex(2), FlexShrink(0.5), FlexBasisPercent(10),
+		Position(EdgeTop, 5), PositionPercent(EdgeLeft, 10),
+		MarginPercent(EdgeTop, 3), MarginAuto(EdgeRight),
+		PaddingPercent(EdgeBottom, 4), Border(EdgeAll, 1),
+		Gap(GutterColumn, 6), GapPercent(GutterRow, 7),
+		WidthPercent(50), HeightAuto(),
+		MinWidth(10), MinHeightPercent(20), MaxWidthPercent(90), MaxHeight(300),
+		AspectRatio(2),
+	)
+
+	node := NewNode()
+	node.StyleSetFlexDirection(FlexDirectionColumnReverse)
+	node.StyleSetDirection(DirectionRTL)
+	node.StyleSetFlexWrap(WrapWrap)
+	node.StyleSetOverflow(OverflowHidden)
+	node.StyleSetPositionType(PositionTypeAbsolute)
+	node.StyleSetAlignItems(AlignCenter)
+	node.StyleSetAlignContent(AlignSpaceAround)
+	node.StyleSetFlex(2)
+	node.StyleSetFlexShrink(0.5)
+	node.StyleSetFlexBasisPercent(10)
+	node.StyleSetPosition(EdgeTop, 5)
+	node.StyleSetPositionPercent(EdgeLeft, 10)
+	node.StyleSetMarginPercent(EdgeTop, 3)
+	node.StyleSetMarginAuto(EdgeRight)
+	node.StyleSetPaddingPercent(EdgeBottom, 4)
+	node.StyleSetBorder(EdgeAll, 1)
+	node.StyleSetGap(GutterColumn, 6)
+	node.StyleSetGapPercent(GutterRow, 7)
+	node.StyleSetWidthPercent(50)
+	node.StyleSetHeightAuto()
+	node.StyleSetMinWidth(10)
+	node.StyleSetMinHeightPercent(20)
+	node.StyleSetMaxWidthPercent(90)
+	node.StyleSetMaxHeight(300)
+	node.StyleSetAspectRatio(2)
+
+	assert.True(t, styleEq(&node.Style, &built.Style))
+}
+
+func TestBuilder_grid_and_measure(t *testing.T) {
+	config := NewConfig()
+	text := NewWithConfig(config, Measure(_simulate_wrapping_text))
+	root := NewWithConfig(config, DisplayGrid,
+		GridTemplateColumns(GridTrackPoints(40), GridTrackFr(1)),
+		Width(120),
+		Children(
+			NewWithConfig(config, GridColumn(GridPlacement{Start: 2}), GridRow(GridPlacement{Start: 1})),
+			text,
+		),
+	)
+	assert.Same(t, config, root.Config)
+	assert.Equal(t, NodeTypeText, text.NodeType)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertLayout(t, root.GetChild(0), 40, 0, 80, 32)
+	assertLayout(t, text, 0, 0, 40, 32)
+}
@@ -0,0 +1,77 @@
+package flex
+
+// Clone returns a copy of node with its style, layout and cached measurements.
+// The copy has no parent and shares children with node: a child stays owned
+// by node and is cloned only when the copy lays it out or when it's returned
+// by MutableChild. This lets two trees share unchanged subtrees
+func (node *Node) Clone() *Node {
+	clone := *node
+	clone.Parent = nil
+	clone.NextChild = nil
+	clone.layoutStyle = nil
+	// virtual list keeps creating children of node, children of the clone
+	// are the ones it had when cloned
+	clone.virtualList = nil
+	if node.Children != nil {
+		clone.Children = append([]*Node(nil), node.Children...)
+	}
+	resolveDimensions(&clone)
+	return &clone
+}
+
+// CloneTree returns a copy of node and of all its descendants. The copy has
+// no parent and shares no nodes with node
+func (node *Node) CloneTree() *Node {
+	clone := node.Clone()
+	for i, child := range clone.Children {
+		childClone := child.CloneTree()
+		childClone.Parent = clone
+		clone.Children[i] = childClone
+	}
+	return clone
+}
+
+// MutableChild returns a child at a given index like GetChild. A child shared
+// with another tree is first replaced by its clone, so it can be changed
+// without changing the other tree
+func (node *Node) MutableChild(idx int) *Node {
+	if idx < 0 || idx >= len(node.Children) {
+		return nil
+	}
+	nodeCloneChildIfNeeded(node, idx)
+	return node.Children[idx]
+}
+
+// nodeCloneChildIfNeeded replaces a shared child at idx by its clone owned by
+// node
+func nodeCloneChildIfNeeded(node *Node, idx int) {
+	child := node.Children[idx]
+	if child.Parent == node {
+		return
+	}
+	var clone *Node
+	if cloneNode := node.Config.CloneNodeFunc; cloneNode != nil {
+		clone = cloneNode(child, node, idx)
+	} else {
+		clone = child.Clone()
+	}
+	if clone == nil || clone == child {
+		assertFailed(node.Config, node, "CloneNodeFunc must return a new node")
+		clone = child.Clone()
+	}
+	clone.Parent = node
+	node.Children[idx] = clone
+}
+
+// nodeCloneChildrenIfNeeded makes node own all children it lays out before
+// their layout is changed
+func nodeCloneChildrenIfNeeded(node *Node) {
+	for i, child := range node.Children {
+		if child.Parent != node {
+			nodeCloneChildIfNeeded(node, i)
+		}
+		if child := node.Children[i]; child.Style.Display == DisplayContents {
+			nodeCloneChildrenIfNeeded(child)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCloneTestTree(config *Config) *Node {
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetWidth(50)
+	root.InsertChild(root_child0, 0)
+
+	root_child0_child0 := NewNodeWithConfig(config)
+	root_child0_child0.StyleSetHeight(20)
+	root_child0.InsertChild(root_child0_child0, 0)
+
+	root_child1 := NewNodeWithConfig(config)
+	root_child1.StyleSetFlexGrow(1)
+	root.InsertChild(root_child1, 1)
+	return root
+}
+
+func TestClone_copies_style_and_layout(t *testing.T) {
+	config := NewConfig()
+	root := newCloneTestTree(config)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	clone := root.Clone()
+	assert.Nil(t, clone.Parent)
+	assert.True(t, styleEq(&root.Style, &clone.Style))
+	assert.Equal(t, root.Layout.Position, clone.Layout.Position)
+	assert.Equal(t, root.Layout.Dimensions, clone.Layout.Dimensions)
+	assert.Equal(t, root.Layout.cachedLayout, clone.Layout.cachedLayout)
+	assert.Equal(t, root.Layout.cachedMeasurements, clone.Layout.cachedMeasurements)
+	assert.Equal(t, root.Children, clone.Children)
+
+	// children are shared, they're still owned by root
+	assert.Same(t, root, clone.GetChild(0).Parent)
+
+	// list of children isn't shared
+	clone.RemoveChild(clone.GetChild(1))
+	assert.Equal(t, 2, len(root.Children))
+	assert.Same(t, root, root.GetChild(1).Parent)
+	assert.False(t, root.IsDirty)
+}
+
+func TestClone_tree(t *testing.T) {
+	config := NewConfig()
+	root := newCloneTestTree(config)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	clone := root.CloneTree()
+	assert.Same(t, clone, clone.GetChild(0).Parent)
+	assert.Same(t, clone.GetChild(0), clone.GetChild(0).GetChild(0).Parent)
+	assert.NotSame(t, root.GetChild(0).GetChild(0), clone.GetChild(0).GetChild(0))
+	assertFloatEqual(t, 50, clone.GetChild(1).LayoutGetLeft())
+	assertFloatEqual(t, 50, clone.GetChild(1).LayoutGetWidth())
+
+	clone.GetChild(0).StyleSetWidth(30)
+	assert.True(t, clone.IsDirty)
+	assert.False(t, root.IsDirty)
+	CalculateLayout(clone, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 30, clone.GetChild(1).LayoutGetLeft())
+	assertFloatEqual(t, 70, clone.GetChild(1).LayoutGetWidth())
+	assertFloatEqual(t, 50, root.GetChild(1).LayoutGetLeft())
+	assertFloatEqual(t, 50, root.GetChild(1).LayoutGetWidth())
+}
+
+func TestClone_copy_on_write(t *testing.T) {
+	config := NewConfig()
+	root := newCloneTestTree(config)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	root_child0 := root.GetChild(0)
+	root_child0_child0 := root_child0.GetChild(0)
+	root_child1 := root.GetChild(1)
+
+	clone := root.Clone()
+	clone_child1 := clone.MutableChild(1)
+	assert.NotSame(t, root_child1, clone_child1)
+	assert.Same(t, clone, clone_child1.Parent)
+	assert.Same(t, clone_child1, clone.MutableChild(1))
+
+	clone_child1.StyleSetMargin(EdgeLeft, 10)
+	assert.True(t, clone.IsDirty)
+	assert.False(t, root.IsDirty)
+	assert.False(t, root_child1.IsDirty)
+	CalculateLayout(clone, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 60, clone_child1.LayoutGetLeft())
+	assertFloatEqual(t, 40, clone_child1.LayoutGetWidth())
+	assertFloatEqual(t, 50, root_child1.LayoutGetLeft())
+	assertFloatEqual(t, 50, root_child1.LayoutGetWidth())
+
+	// layout cloned the other child before laying it out, its unchanged
+	// subtree is still shared
+	clone_child0 := clone.GetChild(0)
+	assert.NotSame(t, root_child0, clone_child0)
+	assert.Same(t, clone, clone_child0.Parent)
+	assert.Same(t, root_child0_child0, clone_child0.GetChild(0))
+	assert.Same(t, root_child0, root_child0_child0.Parent)
+	assert.Same(t, root_child0, root.GetChild(0))
+}
+
+func TestClone_clone_node_func(t *testing.T) {
+	config := NewConfig()
+	var cloned []int
+	config.CloneNodeFunc = func(oldNode *Node, owner *Node, childIndex int) *Node {
+		cloned = append(cloned, childIndex)
+		clone := oldNode.Clone()
+		clone.Context = childIndex
+		return clone
+	}
+	root := newCloneTestTree(config)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	clone := root.Clone()
+	clone.StyleSetWidth(200)
+	CalculateLayout(clone, Undefined, Undefined, DirectionLTR)
+
+	assert.Equal(t, []int{0, 1}, cloned)
+	assert.Equal(t, 1, clone.GetChild(1).Context)
+	assertFloatEqual(t, 150, clone.GetChild(1).LayoutGetWidth())
+	assertFloatEqual(t, 50, root.GetChild(1).LayoutGetWidth())
+}
@@ -0,0 +1,122 @@
+package flex
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// run with: go test -race -run Concurrent
+
+func newConcurrencyTestTree(config *Config, seed int) *Node {
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetAlignContent(AlignSpaceBetween)
+	root.StyleSetPadding(EdgeAll, float32(seed%7))
+
+	for i := 0; i < 12; i++ {
+		child := NewNodeWithConfig(config)
+		child.StyleSetFlexGrow(float32(i % 3))
+		child.StyleSetMargin(EdgeAll, float32((seed+i)%5))
+		if i%4 == 0 {
+			child.SetMeasureFunc(func(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+				return Size{Width: float32(10 + seed%13), Height: float32(10 + i)}
+			})
+		} else {
+			child.StyleSetWidth(float32(20 + (seed*i)%30))
+			child.StyleSetHeight(float32(15 + i))
+			grandChild := NewNodeWithConfig(config)
+			grandChild.StyleSetWidthPercent(50)
+			grandChild.StyleSetFlexGrow(1)
+			child.InsertChild(grandChild, 0)
+		}
+		root.InsertChild(child, i)
+	}
+	return root
+}
+
+func collectLayout(node *Node, out []float32) []float32 {
+	out = append(out,
+		node.LayoutGetLeft(),
+		node.LayoutGetTop(),
+		node.LayoutGetWidth(),
+		node.LayoutGetHeight())
+	for _, child := range node.Children {
+		out = collectLayout(child, out)
+	}
+	return out
+}
+
+func layoutConcurrencyTestTree(root *Node, seed int) []float32 {
+	var res []float32
+	for _, width := range []float32{300, 180, 300} {
+		root.StyleSetWidth(width + float32(seed%11))
+		CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+		res = collectLayout(root, res)
+	}
+	return res
+}
+
+func testConcurrentLayout(t *testing.T, newConfig func() *Config) {
+	const treeCount = 200
+
+	expected := make([][]float32, treeCount)
+	for i := 0; i < treeCount; i++ {
+		root := newConcurrencyTestTree(newConfig(), i)
+		expected[i] = layoutConcurrencyTestTree(root, i)
+	}
+
+	got := make([][]float32, treeCount)
+	var wg sync.WaitGroup
+	for i := 0; i < treeCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			root := newConcurrencyTestTree(newConfig(), i)
+			got[i] = layoutConcurrencyTestTree(root, i)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < treeCount; i++ {
+		assert.Equal(t, expected[i], got[i], "tree %d", i)
+	}
+}
+
+func TestConcurrent_layout_independent_configs(t *testing.T) {
+	testConcurrentLayout(t, NewConfig)
+}
+
+func TestConcurrent_layout_shared_config(t *testing.T) {
+	config := NewConfig()
+	testConcurrentLayout(t, func() *Config {
+		return config
+	})
+}
+
+func TestConcurrent_layout_default_config(t *testing.T) {
+	testConcurrentLayout(t, ConfigGetDefault)
+}
+
+func TestConcurrent_layout_generation_is_per_call(t *testing.T) {
+	root := NewNode()
+	root.StyleSetWidth(100)
+	child := NewNode()
+	child.StyleSetFlexGrow(1)
+	root.InsertChild(child, 0)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	first := root.Layout.generationCount
+
+	// another tree laid out in between must not confuse this one
+	other := NewNode()
+	CalculateLayout(other, 50, 50, DirectionLTR)
+
+	root.StyleSetWidth(200)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.NotEqual(t, first, root.Layout.generationCount)
+	assert.Equal(t, root.Layout.generationCount, child.Layout.generationCount)
+	assertFloatEqual(t, 200, child.LayoutGetWidth())
+}
@@ -0,0 +1,100 @@
+package flex
+
+import (
+	"testing"
+)
+
+func TestContentDimensions_scroll_column(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetOverflow(OverflowScroll)
+	root.StyleSetPadding(EdgeAll, 10)
+	root.StyleSetBorder(EdgeAll, 2)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetHeight(60)
+	root.InsertChild(root_child0, 0)
+
+	root_child1 := NewNodeWithConfig(config)
+	root_child1.StyleSetMargin(EdgeBottom, 5)
+	root_child1.StyleSetHeight(60)
+	root.InsertChild(root_child1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 96, root.LayoutGetContentWidth())
+	assertFloatEqual(t, 145, root.LayoutGetContentHeight())
+	assertFloatEqual(t, 76, root_child0.LayoutGetContentWidth())
+	assertFloatEqual(t, 60, root_child0.LayoutGetContentHeight())
+
+	root_child1.StyleSetHeight(10)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 96, root.LayoutGetContentWidth())
+	assertFloatEqual(t, 96, root.LayoutGetContentHeight())
+}
+
+func TestContentDimensions_scroll_row(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetOverflow(OverflowScroll)
+	root.StyleSetPadding(EdgeAll, 10)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetWidth(150)
+	root.InsertChild(root_child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 10, root_child0.LayoutGetLeft())
+	assertFloatEqual(t, 170, root.LayoutGetContentWidth())
+	assertFloatEqual(t, 100, root.LayoutGetContentHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, -60, root_child0.LayoutGetLeft())
+	assertFloatEqual(t, 170, root.LayoutGetContentWidth())
+	assertFloatEqual(t, 100, root.LayoutGetContentHeight())
+}
+
+func TestContentDimensions_visible_overflow_of_descendants(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetOverflow(OverflowScroll)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	root_child0 := NewNodeWithConfig(config)
+	root_child0.StyleSetPadding(EdgeLeft, 5)
+	root_child0.StyleSetWidth(50)
+	root_child0.StyleSetHeight(50)
+	root.InsertChild(root_child0, 0)
+
+	root_child0_child0 := NewNodeWithConfig(config)
+	root_child0_child0.StyleSetPositionType(PositionTypeAbsolute)
+	root_child0_child0.StyleSetPosition(EdgeLeft, 100)
+	root_child0_child0.StyleSetPosition(EdgeTop, 120)
+	root_child0_child0.StyleSetWidth(20)
+	root_child0_child0.StyleSetHeight(30)
+	root_child0.InsertChild(root_child0_child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 120, root_child0.LayoutGetContentWidth())
+	assertFloatEqual(t, 150, root_child0.LayoutGetContentHeight())
+	assertFloatEqual(t, 120, root.LayoutGetContentWidth())
+	assertFloatEqual(t, 150, root.LayoutGetContentHeight())
+
+	// hidden overflow of child doesn't extend content
+	root_child0.StyleSetOverflow(OverflowHidden)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 120, root_child0.LayoutGetContentWidth())
+	assertFloatEqual(t, 100, root.LayoutGetContentWidth())
+	assertFloatEqual(t, 100, root.LayoutGetContentHeight())
+}
@@ -0,0 +1,897 @@
+package flex
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// StyleParseError describes an error in CSS declarations passed to
+// ParseStyle or Node.ApplyStyle
+type StyleParseError struct {
+	// Offset is byte offset of the error in the input
+	Offset int
+	// Line and Column are 1-based position of the error in the input
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *StyleParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ParseStyle parses CSS declarations like "flex-direction: row; margin: 4px 8px"
+// into a Style. Properties that are not in css have default values
+func ParseStyle(css string) (Style, error) {
+	style := nodeDefaults.Style
+	err := StyleApplyCSS(&style, css)
+	return style, err
+}
+
+// StyleApplyCSS sets properties of style from CSS declarations. On error
+// style is left unchanged
+func StyleApplyCSS(style *Style, css string) error {
+	p := &cssParser{src: css}
+	res := *style
+	if err := p.parse(&res); err != nil {
+		return err
+	}
+	*style = res
+	return nil
+}
+
+// ApplyStyle sets style properties of node from CSS declarations like
+// "flex-direction: row; margin: 4px 8px". On error node is left unchanged
+func (node *Node) ApplyStyle(css string) error {
+	style := node.Style
+	if err := StyleApplyCSS(&style, css); err != nil {
+		return err
+	}
+	if !styleEq(&node.Style, &style) {
+		node.Style = style
+		nodeMarkDirtyInternal(node)
+	}
+	return nil
+}
+
+type cssToken struct {
+	text string
+	pos  int
+}
+
+type cssParser struct {
+	src string
+	// nodePrinter accepts NodePrinter output, which differs from CSS:
+	// "flexWrap" is "flex-wrap" and "flex" is Style.Flex
+	nodePrinter bool
+}
+
+// textPosition returns 1-based line and column of byte offset in s
+func textPosition(s string, offset int) (int, int) {
+	line, col := 1, 1
+	for _, c := range s[:offset] {
+		if c == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
+
+func (p *cssParser) errorf(pos int, format string, args ...interface{}) error {
+	line, col := textPosition(p.src, pos)
+	return &StyleParseError{
+		Offset: pos,
+		Line:   line,
+		Column: col,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+// stripComments replaces /* */ comments with spaces so that offsets
+// of the remaining text don't change
+func (p *cssParser) stripComments() (string, error) {
+	s := p.src
+	if !strings.Contains(s, "/*") {
+		return s, nil
+	}
+	b := []byte(s)
+	for i := 0; i+1 < len(b); i++ {
+		if b[i] != '/' || b[i+1] != '*' {
+			continue
+		}
+		end := strings.Index(s[i+2:], "*/")
+		if end == -1 {
+			return "", p.errorf(i, "unterminated comment")
+		}
+		end += i + 4
+		for ; i < end; i++ {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+		i--
+	}
+	return string(b), nil
+}
+
+func isCSSSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// fields splits s[start:end] on whitespace
+func fields(s string, start int, end int) []cssToken {
+	var res []cssToken
+	i := start
+	for i < end {
+		for i < end && isCSSSpace(s[i]) {
+			i++
+		}
+		tokStart := i
+		for i < end && !isCSSSpace(s[i]) {
+			i++
+		}
+		if i > tokStart {
+			res = append(res, cssToken{text: s[tokStart:i], pos: tokStart})
+		}
+	}
+	return res
+}
+
+func (p *cssParser) parse(style *Style) error {
+	s, err := p.stripComments()
+	if err != nil {
+		return err
+	}
+	start := 0
+	for start <= len(s) {
+		end := strings.IndexByte(s[start:], ';')
+		if end == -1 {
+			end = len(s)
+		} else {
+			end += start
+		}
+		if err := p.parseDeclaration(style, s, start, end); err != nil {
+			return err
+		}
+		start = end + 1
+	}
+	return nil
+}
+
+func (p *cssParser) parseDeclaration(style *Style, s string, start int, end int) error {
+	for start < end && isCSSSpace(s[start]) {
+		start++
+	}
+	if start == end {
+		return nil
+	}
+	colon := strings.IndexByte(s[start:end], ':')
+	if colon == -1 {
+		return p.errorf(start, "expected ':' after property name")
+	}
+	colon += start
+	names := fields(s, start, colon)
+	if len(names) == 0 {
+		return p.errorf(start, "missing property name")
+	}
+	if len(names) > 1 {
+		return p.errorf(names[1].pos, "unexpected %q in property name", names[1].text)
+	}
+	name := cssToken{text: strings.ToLower(names[0].text), pos: names[0].pos}
+	args := fields(s, colon+1, end)
+	if len(args) == 0 {
+		return p.errorf(colon+1, "missing value for %s", name.text)
+	}
+	for i := range args {
+		args[i].text = strings.ToLower(args[i].text)
+	}
+	return p.applyProperty(style, name, args)
+}
+
+func (p *cssParser) applyProperty(style *Style, name cssToken, args []cssToken) error {
+	if p.nodePrinter {
+		switch name.text {
+		case "flexwrap":
+			name.text = "flex-wrap"
+		case "flex":
+			return p.parseSingle(args, func(arg cssToken) (err error) {
+				style.Flex, err = p.parseNumber(arg, arg.text)
+				return err
+			})
+		}
+	}
+
+	switch name.text {
+	case "direction":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.Direction, err = ParseDirection(s)
+			return err
+		})
+	case "flex-direction":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.FlexDirection, err = ParseFlexDirection(s)
+			return err
+		})
+	case "justify-content":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.JustifyContent, err = ParseJustify(s)
+			return err
+		})
+	case "align-content":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.AlignContent, err = ParseAlign(s)
+			return err
+		})
+	case "align-items":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.AlignItems, err = ParseAlign(s)
+			return err
+		})
+	case "align-self":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.AlignSelf, err = ParseAlign(s)
+			return err
+		})
+	case "position":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.PositionType, err = ParsePositionType(s)
+			return err
+		})
+	case "flex-wrap":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.FlexWrap, err = ParseWrap(s)
+			return err
+		})
+	case "overflow":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.Overflow, err = ParseOverflow(s)
+			return err
+		})
+	case "display":
+		return p.parseKeyword(args, func(s string) (err error) {
+			style.Display, err = ParseDisplay(s)
+			return err
+		})
+	case "flex-flow":
+		return p.parseFlexFlow(style, args)
+	case "flex":
+		return p.parseFlex(style, args)
+	case "flex-grow":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseNonNegative(arg, &style.FlexGrow)
+		})
+	case "flex-shrink":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseNonNegative(arg, &style.FlexShrink)
+		})
+	case "flex-basis":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, &style.FlexBasis, cssAllowAuto|cssAllowPercent|cssNonNegative)
+		})
+	case "width":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, &style.Dimensions[DimensionWidth], cssAllowAuto|cssAllowPercent|cssNonNegative)
+		})
+	case "height":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, &style.Dimensions[DimensionHeight], cssAllowAuto|cssAllowPercent|cssNonNegative)
+		})
+	case "min-width":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, &style.MinDimensions[DimensionWidth], cssAutoIsUndefined|cssAllowPercent|cssNonNegative)
+		})
+	case "min-height":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, &style.MinDimensions[DimensionHeight], cssAutoIsUndefined|cssAllowPercent|cssNonNegative)
+		})
+	case "max-width":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, &style.MaxDimensions[DimensionWidth], cssNoneIsUndefined|cssAllowPercent|cssNonNegative)
+		})
+	case "max-height":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, &style.MaxDimensions[DimensionHeight], cssNoneIsUndefined|cssAllowPercent|cssNonNegative)
+		})
+	case "aspect-ratio":
+		return p.parseAspectRatio(style, args)
+	case "margin":
+		return p.parseEdges(style.Margin[:], args, cssAllowAuto|cssAllowPercent)
+	case "padding":
+		return p.parseEdges(style.Padding[:], args, cssAllowPercent|cssNonNegative)
+	case "border", "border-width":
+		return p.parseEdges(style.Border[:], args, cssNonNegative)
+	case "gap":
+		return p.parseGap(style, args)
+	case "row-gap":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, &style.Gap[GutterRow], cssAllowPercent|cssNonNegative)
+		})
+	case "column-gap":
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, &style.Gap[GutterColumn], cssAllowPercent|cssNonNegative)
+		})
+	case "grid-template-columns":
+		return p.parseGridTracks(args, &style.GridTemplateColumns)
+	case "grid-template-rows":
+		return p.parseGridTracks(args, &style.GridTemplateRows)
+	case "grid-column":
+		return p.parseGridPlacement(args, &style.GridColumn)
+	case "grid-row":
+		return p.parseGridPlacement(args, &style.GridRow)
+	case "grid-column-start":
+		return p.parseGridLineProperty(args, &style.GridColumn, true)
+	case "grid-column-end":
+		return p.parseGridLineProperty(args, &style.GridColumn, false)
+	case "grid-row-start":
+		return p.parseGridLineProperty(args, &style.GridRow, true)
+	case "grid-row-end":
+		return p.parseGridLineProperty(args, &style.GridRow, false)
+	}
+
+	for edge := EdgeLeft; edge < EdgeCount; edge++ {
+		edgeName := EdgeToString(edge)
+		var value *Value
+		var flags int
+		switch name.text {
+		case cssInsetProperty(edge):
+			value, flags = &style.Position[edge], cssAutoIsUndefined|cssAllowPercent
+		case "margin-" + edgeName:
+			value, flags = &style.Margin[edge], cssAllowAuto|cssAllowPercent
+		case "padding-" + edgeName:
+			value, flags = &style.Padding[edge], cssAllowPercent|cssNonNegative
+		case "border-" + edgeName, "border-" + edgeName + "-width":
+			value, flags = &style.Border[edge], cssNonNegative
+		default:
+			continue
+		}
+		return p.parseSingle(args, func(arg cssToken) error {
+			return p.parseLength(arg, value, flags)
+		})
+	}
+	return p.errorf(name.pos, "unknown property %q", name.text)
+}
+
+// cssInsetProperty returns name of the property of inset at edge. Insets of
+// single edges are named like in CSS, "left" or "start", insets of
+// several edges are "inset-horizontal", "inset-vertical" and "inset-all"
+func cssInsetProperty(edge Edge) string {
+	if edge >= EdgeHorizontal {
+		return "inset-" + EdgeToString(edge)
+	}
+	return EdgeToString(edge)
+}
+
+// cssKeyword maps CSS spelling of a keyword to the spelling of *ToString
+// functions
+func cssKeyword(s string) string {
+	if s == "nowrap" {
+		return WrapToString(WrapNoWrap)
+	}
+	return s
+}
+
+func (p *cssParser) parseKeyword(args []cssToken, parse func(s string) error) error {
+	return p.parseSingle(args, func(arg cssToken) error {
+		if err := parse(cssKeyword(arg.text)); err != nil {
+			return p.errorf(arg.pos, "invalid value %q", arg.text)
+		}
+		return nil
+	})
+}
+
+func (p *cssParser) parseSingle(args []cssToken, parse func(arg cssToken) error) error {
+	if len(args) > 1 {
+		return p.errorf(args[1].pos, "unexpected %q", args[1].text)
+	}
+	return parse(args[0])
+}
+
+const (
+	cssAllowAuto = 1 << iota
+	cssAutoIsUndefined
+	cssNoneIsUndefined
+	cssAllowPercent
+	cssNonNegative
+)
+
+func (p *cssParser) parseNumber(arg cssToken, s string) (float32, error) {
+	f, err := strconv.ParseFloat(s, 32)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, p.errorf(arg.pos, "invalid number %q", arg.text)
+	}
+	return float32(f), nil
+}
+
+func (p *cssParser) parseNonNegative(arg cssToken, v *float32) error {
+	f, err := p.parseNumber(arg, arg.text)
+	if err != nil {
+		return err
+	}
+	if f < 0 {
+		return p.errorf(arg.pos, "negative value %q", arg.text)
+	}
+	*v = f
+	return nil
+}
+
+// parseLength parses "auto", "10px", "50%" or unitless "10", which is
+// treated as points
+func (p *cssParser) parseLength(arg cssToken, v *Value, flags int) error {
+	s := arg.text
+	switch {
+	case s == "auto" && flags&cssAllowAuto != 0:
+		*v = autoValue
+		return nil
+	case s == "auto" && flags&cssAutoIsUndefined != 0,
+		s == "none" && flags&cssNoneIsUndefined != 0:
+		*v = undefinedValue
+		return nil
+	}
+
+	unit := UnitPoint
+	if strings.HasSuffix(s, "%") {
+		if flags&cssAllowPercent == 0 {
+			return p.errorf(arg.pos, "percent value %q not allowed", arg.text)
+		}
+		unit = UnitPercent
+		s = s[:len(s)-1]
+	} else if strings.HasSuffix(s, "px") {
+		s = s[:len(s)-2]
+	}
+	f, err := p.parseNumber(arg, s)
+	if err != nil {
+		return p.errorf(arg.pos, "invalid length %q", arg.text)
+	}
+	if f < 0 && flags&cssNonNegative != 0 {
+		return p.errorf(arg.pos, "negative value %q", arg.text)
+	}
+	*v = Value{Value: f, Unit: unit}
+	return nil
+}
+
+// parseEdges parses 1 to 4 values of margin, padding and border shorthands
+// in CSS order: top, right, bottom, left
+func (p *cssParser) parseEdges(edges []Value, args []cssToken, flags int) error {
+	if len(args) > 4 {
+		return p.errorf(args[4].pos, "unexpected %q", args[4].text)
+	}
+	var values [4]Value
+	for i, arg := range args {
+		if err := p.parseLength(arg, &values[i], flags); err != nil {
+			return err
+		}
+	}
+	// shorthand overrides all edges set before
+	for i := range edges {
+		edges[i] = undefinedValue
+	}
+	switch len(args) {
+	case 1:
+		edges[EdgeAll] = values[0]
+	case 2:
+		edges[EdgeVertical] = values[0]
+		edges[EdgeHorizontal] = values[1]
+	case 3:
+		edges[EdgeTop] = values[0]
+		edges[EdgeHorizontal] = values[1]
+		edges[EdgeBottom] = values[2]
+	case 4:
+		edges[EdgeTop] = values[0]
+		edges[EdgeRight] = values[1]
+		edges[EdgeBottom] = values[2]
+		edges[EdgeLeft] = values[3]
+	}
+	return nil
+}
+
+// parseGap parses "gap: <row-gap> <column-gap>?"
+func (p *cssParser) parseGap(style *Style, args []cssToken) error {
+	if len(args) > 2 {
+		return p.errorf(args[2].pos, "unexpected %q", args[2].text)
+	}
+	var values [2]Value
+	for i, arg := range args {
+		if err := p.parseLength(arg, &values[i], cssAllowPercent|cssNonNegative); err != nil {
+			return err
+		}
+	}
+	if len(args) == 1 {
+		style.Gap[GutterAll] = values[0]
+		style.Gap[GutterRow] = undefinedValue
+		style.Gap[GutterColumn] = undefinedValue
+	} else {
+		style.Gap[GutterAll] = undefinedValue
+		style.Gap[GutterRow] = values[0]
+		style.Gap[GutterColumn] = values[1]
+	}
+	return nil
+}
+
+// parseFlexFlow parses "flex-flow: <flex-direction> || <flex-wrap>"
+func (p *cssParser) parseFlexFlow(style *Style, args []cssToken) error {
+	if len(args) > 2 {
+		return p.errorf(args[2].pos, "unexpected %q", args[2].text)
+	}
+	direction, wrap := nodeDefaults.Style.FlexDirection, nodeDefaults.Style.FlexWrap
+	hasDirection, hasWrap := false, false
+	for _, arg := range args {
+		keyword := cssKeyword(arg.text)
+		if v, err := ParseFlexDirection(keyword); err == nil && !hasDirection {
+			direction, hasDirection = v, true
+		} else if v, err := ParseWrap(keyword); err == nil && !hasWrap {
+			wrap, hasWrap = v, true
+		} else {
+			return p.errorf(arg.pos, "invalid value %q", arg.text)
+		}
+	}
+	style.FlexDirection = direction
+	style.FlexWrap = wrap
+	return nil
+}
+
+// parseFlex parses "flex" shorthand the way CSS does: "none" is "0 0 auto",
+// "auto" is "1 1 auto" and omitted flex-basis is 0
+func (p *cssParser) parseFlex(style *Style, args []cssToken) error {
+	if len(args) == 1 && (args[0].text == "none" || args[0].text == "auto") {
+		grow := float32(0)
+		if args[0].text == "auto" {
+			grow = 1
+		}
+		style.Flex = Undefined
+		style.FlexGrow = grow
+		style.FlexShrink = grow
+		style.FlexBasis = autoValue
+		return nil
+	}
+	if len(args) > 3 {
+		return p.errorf(args[3].pos, "unexpected %q", args[3].text)
+	}
+
+	grow, shrink := float32(1), float32(1)
+	basis := Value{Value: 0, Unit: UnitPoint}
+	numbers := 0
+	hasBasis := false
+	prevWasFactor := false
+	for _, arg := range args {
+		// flex factors must be next to each other, a unitless 0 is a factor
+		isFactor := numbers == 0 || (numbers == 1 && prevWasFactor)
+		prevWasFactor = false
+		if _, err := strconv.ParseFloat(arg.text, 32); err == nil && isFactor {
+			v := &grow
+			if numbers == 1 {
+				v = &shrink
+			}
+			if err := p.parseNonNegative(arg, v); err != nil {
+				return err
+			}
+			numbers++
+			prevWasFactor = true
+			continue
+		}
+		if hasBasis {
+			return p.errorf(arg.pos, "unexpected %q", arg.text)
+		}
+		if err := p.parseLength(arg, &basis, cssAllowAuto|cssAllowPercent|cssNonNegative); err != nil {
+			return err
+		}
+		hasBasis = true
+	}
+	style.Flex = Undefined
+	style.FlexGrow = grow
+	style.FlexShrink = shrink
+	style.FlexBasis = basis
+	return nil
+}
+
+// splitTokens splits args further on any of separators, which become
+// tokens of their own
+func splitTokens(args []cssToken, separators string) []cssToken {
+	var parts []cssToken
+	for _, arg := range args {
+		s, pos := arg.text, arg.pos
+		for {
+			i := strings.IndexAny(s, separators)
+			if i == -1 {
+				break
+			}
+			if i > 0 {
+				parts = append(parts, cssToken{text: s[:i], pos: pos})
+			}
+			parts = append(parts, cssToken{text: s[i : i+1], pos: pos + i})
+			s, pos = s[i+1:], pos+i+1
+		}
+		if s != "" {
+			parts = append(parts, cssToken{text: s, pos: pos})
+		}
+	}
+	return parts
+}
+
+// parseAspectRatio parses "aspect-ratio: 1.5" or "aspect-ratio: 16 / 9"
+func (p *cssParser) parseAspectRatio(style *Style, args []cssToken) error {
+	parts := splitTokens(args, "/")
+
+	if len(parts) == 1 && parts[0].text == "auto" {
+		style.AspectRatio = Undefined
+		return nil
+	}
+	var ratio float32
+	if err := p.parseNonNegative(parts[0], &ratio); err != nil {
+		return err
+	}
+	if len(parts) == 1 {
+		style.AspectRatio = ratio
+		return nil
+	}
+	if parts[1].text != "/" {
+		return p.errorf(parts[1].pos, "expected '/'")
+	}
+	if len(parts) == 2 {
+		return p.errorf(parts[1].pos+1, "missing value after '/'")
+	}
+	if len(parts) > 3 {
+		return p.errorf(parts[3].pos, "unexpected %q", parts[3].text)
+	}
+	var denom float32
+	if err := p.parseNonNegative(parts[2], &denom); err != nil {
+		return err
+	}
+	if denom == 0 {
+		return p.errorf(parts[2].pos, "invalid aspect ratio")
+	}
+	style.AspectRatio = ratio / denom
+	return nil
+}
+
+// cssTokenReader reads tokens of a value split by splitTokens
+type cssTokenReader struct {
+	p    *cssParser
+	toks []cssToken
+	i    int
+}
+
+func newCSSTokenReader(p *cssParser, args []cssToken, separators string) *cssTokenReader {
+	return &cssTokenReader{p: p, toks: splitTokens(args, separators)}
+}
+
+func (r *cssTokenReader) more() bool {
+	return r.i < len(r.toks)
+}
+
+func (r *cssTokenReader) next() (cssToken, error) {
+	if !r.more() {
+		end := 0
+		if len(r.toks) > 0 {
+			last := r.toks[len(r.toks)-1]
+			end = last.pos + len(last.text)
+		}
+		return cssToken{}, r.p.errorf(end, "unexpected end of value")
+	}
+	tok := r.toks[r.i]
+	r.i++
+	return tok, nil
+}
+
+func (r *cssTokenReader) expect(s string) error {
+	tok, err := r.next()
+	if err != nil {
+		return err
+	}
+	if tok.text != s {
+		return r.p.errorf(tok.pos, "expected '%s'", s)
+	}
+	return nil
+}
+
+func (r *cssTokenReader) parseInt() (cssToken, int, error) {
+	tok, err := r.next()
+	if err != nil {
+		return tok, 0, err
+	}
+	n, err := strconv.Atoi(tok.text)
+	if err != nil {
+		return tok, 0, r.p.errorf(tok.pos, "invalid integer %q", tok.text)
+	}
+	return tok, n, nil
+}
+
+// parseGridTracks parses grid-template-columns and grid-template-rows:
+// "none" or a list of "100px", "50%", "auto", "1fr", "minmax(<min>, <max>)"
+// and "repeat(<count>, <tracks>)"
+func (p *cssParser) parseGridTracks(args []cssToken, tracks *[]GridTrack) error {
+	if len(args) == 1 && args[0].text == "none" {
+		*tracks = nil
+		return nil
+	}
+	r := newCSSTokenReader(p, args, "(),")
+	var res []GridTrack
+	for r.more() {
+		tok := r.toks[r.i]
+		if tok.text != "repeat" {
+			track, err := r.parseGridTrack()
+			if err != nil {
+				return err
+			}
+			res = append(res, track)
+			continue
+		}
+		r.i++
+		if err := r.expect("("); err != nil {
+			return err
+		}
+		countTok, count, err := r.parseInt()
+		if err != nil {
+			return err
+		}
+		if count < 1 || count > gridMaxTracks {
+			return p.errorf(countTok.pos, "invalid repeat count %q", countTok.text)
+		}
+		if err := r.expect(","); err != nil {
+			return err
+		}
+		var repeated []GridTrack
+		for r.more() && r.toks[r.i].text != ")" {
+			track, err := r.parseGridTrack()
+			if err != nil {
+				return err
+			}
+			repeated = append(repeated, track)
+		}
+		if len(repeated) == 0 {
+			return p.errorf(countTok.pos, "missing tracks in repeat()")
+		}
+		if err := r.expect(")"); err != nil {
+			return err
+		}
+		if len(res)+count*len(repeated) > gridMaxTracks {
+			return p.errorf(countTok.pos, "too many tracks in repeat()")
+		}
+		res = append(res, GridRepeat(count, repeated...)...)
+	}
+	*tracks = res
+	return nil
+}
+
+// parseGridTrack parses a track size or "minmax(<min>, <max>)"
+func (r *cssTokenReader) parseGridTrack() (GridTrack, error) {
+	tok, err := r.next()
+	if err != nil {
+		return GridTrack{}, err
+	}
+	if tok.text != "minmax" {
+		return r.p.parseGridTrackSize(tok)
+	}
+	if err := r.expect("("); err != nil {
+		return GridTrack{}, err
+	}
+	minTok, err := r.next()
+	if err != nil {
+		return GridTrack{}, err
+	}
+	min, err := r.p.parseGridTrackSize(minTok)
+	if err != nil {
+		return GridTrack{}, err
+	}
+	if min.Fr > 0 {
+		return GridTrack{}, r.p.errorf(minTok.pos, "flexible minimum %q not allowed", minTok.text)
+	}
+	if err := r.expect(","); err != nil {
+		return GridTrack{}, err
+	}
+	maxTok, err := r.next()
+	if err != nil {
+		return GridTrack{}, err
+	}
+	max, err := r.p.parseGridTrackSize(maxTok)
+	if err != nil {
+		return GridTrack{}, err
+	}
+	if err := r.expect(")"); err != nil {
+		return GridTrack{}, err
+	}
+	return GridTrackMinMax(min, max), nil
+}
+
+// parseGridTrackSize parses "100px", "50%", "auto" or "1fr"
+func (p *cssParser) parseGridTrackSize(tok cssToken) (GridTrack, error) {
+	if strings.HasSuffix(tok.text, "fr") {
+		fr, err := p.parseNumber(tok, strings.TrimSuffix(tok.text, "fr"))
+		if err != nil || fr <= 0 {
+			return GridTrack{}, p.errorf(tok.pos, "invalid flex factor %q", tok.text)
+		}
+		return GridTrackFr(fr), nil
+	}
+	var v Value
+	if err := p.parseLength(tok, &v, cssAllowAuto|cssAllowPercent|cssNonNegative); err != nil {
+		return GridTrack{}, err
+	}
+	return GridTrack{Min: v, Max: v}, nil
+}
+
+// parseGridPlacement parses grid-column and grid-row: "<line> / <line>"
+// or "<line>", where line is "auto", an integer or "span <integer>"
+func (p *cssParser) parseGridPlacement(args []cssToken, placement *GridPlacement) error {
+	r := newCSSTokenReader(p, args, "/")
+	res := GridPlacement{}
+	line, span, err := r.parseGridLine()
+	if err != nil {
+		return err
+	}
+	res.Start, res.Span = line, span
+	if r.more() {
+		if err := r.expect("/"); err != nil {
+			return err
+		}
+		line, span, err := r.parseGridLine()
+		if err != nil {
+			return err
+		}
+		res.End = line
+		if res.Span == 0 {
+			res.Span = span
+		}
+	}
+	if r.more() {
+		return p.errorf(r.toks[r.i].pos, "unexpected %q", r.toks[r.i].text)
+	}
+	*placement = res
+	return nil
+}
+
+// parseGridLineProperty parses grid-column-start, grid-column-end,
+// grid-row-start and grid-row-end
+func (p *cssParser) parseGridLineProperty(args []cssToken, placement *GridPlacement, start bool) error {
+	r := newCSSTokenReader(p, args, "")
+	line, span, err := r.parseGridLine()
+	if err != nil {
+		return err
+	}
+	if r.more() {
+		return p.errorf(r.toks[r.i].pos, "unexpected %q", r.toks[r.i].text)
+	}
+	if start {
+		placement.Start = line
+	} else {
+		placement.End = line
+	}
+	if span != 0 {
+		placement.Span = span
+	}
+	return nil
+}
+
+// parseGridLine parses "auto", an integer line or "span <integer>". span is
+// 0 unless it's "span <integer>"
+func (r *cssTokenReader) parseGridLine() (line int, span int, err error) {
+	tok, err := r.next()
+	if err != nil {
+		return 0, 0, err
+	}
+	switch tok.text {
+	case "auto":
+		return 0, 0, nil
+	case "span":
+		spanTok, span, err := r.parseInt()
+		if err != nil {
+			return 0, 0, err
+		}
+		if span < 1 || span > gridMaxTracks {
+			return 0, 0, r.p.errorf(spanTok.pos, "invalid span %q", spanTok.text)
+		}
+		return 0, span, nil
+	}
+	r.i--
+	lineTok, line, err := r.parseInt()
+	if err != nil {
+		return 0, 0, err
+	}
+	if line == 0 || line > gridMaxTracks+1 || line < -gridMaxTracks-1 {
+		return 0, 0, r.p.errorf(lineTok.pos, "invalid grid line %q", lineTok.text)
+	}
+	return line, 0, nil
+}
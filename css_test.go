@@ -0,0 +1,235 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_style_keywords(t *testing.T) {
+	style, err := ParseStyle(`
+		direction: rtl;
+		flex-direction: row-reverse;
+		justify-content: space-evenly;
+		align-content: space-between;
+		align-items: center;
+		align-self: flex-end;
+		position: absolute;
+		flex-wrap: nowrap;
+		overflow: hidden;
+		display: none;
+	`)
+	assert.NoError(t, err)
+	assert.Equal(t, DirectionRTL, style.Direction)
+	assert.Equal(t, FlexDirectionRowReverse, style.FlexDirection)
+	assert.Equal(t, JustifySpaceEvenly, style.JustifyContent)
+	assert.Equal(t, AlignSpaceBetween, style.AlignContent)
+	assert.Equal(t, AlignCenter, style.AlignItems)
+	assert.Equal(t, AlignFlexEnd, style.AlignSelf)
+	assert.Equal(t, PositionTypeAbsolute, style.PositionType)
+	assert.Equal(t, WrapNoWrap, style.FlexWrap)
+	assert.Equal(t, OverflowHidden, style.Overflow)
+	assert.Equal(t, DisplayNone, style.Display)
+
+	style, err = ParseStyle("FLEX-WRAP: Wrap-Reverse")
+	assert.NoError(t, err)
+	assert.Equal(t, WrapWrapReverse, style.FlexWrap)
+}
+
+func TestParse_style_defaults(t *testing.T) {
+	style, err := ParseStyle("")
+	assert.NoError(t, err)
+	assert.True(t, styleEq(&nodeDefaults.Style, &style))
+
+	style, err = ParseStyle(" ; ;/* nothing */")
+	assert.NoError(t, err)
+	assert.True(t, styleEq(&nodeDefaults.Style, &style))
+}
+
+func TestParse_style_lengths(t *testing.T) {
+	style, err := ParseStyle("width: 50%; height: 20px; min-width: 10; max-height: 30.5px; flex-basis: auto; left: 5px; end: 10%")
+	assert.NoError(t, err)
+	assert.Equal(t, Value{50, UnitPercent}, style.Dimensions[DimensionWidth])
+	assert.Equal(t, Value{20, UnitPoint}, style.Dimensions[DimensionHeight])
+	assert.Equal(t, Value{10, UnitPoint}, style.MinDimensions[DimensionWidth])
+	assert.Equal(t, Value{30.5, UnitPoint}, style.MaxDimensions[DimensionHeight])
+	assert.Equal(t, UnitAuto, style.FlexBasis.Unit)
+	assert.Equal(t, Value{5, UnitPoint}, style.Position[EdgeLeft])
+	assert.Equal(t, Value{10, UnitPercent}, style.Position[EdgeEnd])
+
+	style, err = ParseStyle("width: 10px; width: auto; max-width: 10px; max-width: none; min-height: 5px; min-height: auto")
+	assert.NoError(t, err)
+	assert.Equal(t, UnitAuto, style.Dimensions[DimensionWidth].Unit)
+	assert.Equal(t, UnitUndefined, style.MaxDimensions[DimensionWidth].Unit)
+	assert.Equal(t, UnitUndefined, style.MinDimensions[DimensionHeight].Unit)
+}
+
+func TestParse_style_edges(t *testing.T) {
+	style, err := ParseStyle("margin: 4px")
+	assert.NoError(t, err)
+	assert.Equal(t, Value{4, UnitPoint}, style.Margin[EdgeAll])
+
+	style, err = ParseStyle("margin: 4px 8px")
+	assert.NoError(t, err)
+	assert.Equal(t, Value{4, UnitPoint}, style.Margin[EdgeVertical])
+	assert.Equal(t, Value{8, UnitPoint}, style.Margin[EdgeHorizontal])
+	assert.Equal(t, UnitUndefined, style.Margin[EdgeAll].Unit)
+
+	style, err = ParseStyle("padding: 1px 2% 3px")
+	assert.NoError(t, err)
+	assert.Equal(t, Value{1, UnitPoint}, style.Padding[EdgeTop])
+	assert.Equal(t, Value{2, UnitPercent}, style.Padding[EdgeHorizontal])
+	assert.Equal(t, Value{3, UnitPoint}, style.Padding[EdgeBottom])
+
+	style, err = ParseStyle("border-width: 1px 2px 3px 4px")
+	assert.NoError(t, err)
+	assert.Equal(t, Value{1, UnitPoint}, style.Border[EdgeTop])
+	assert.Equal(t, Value{2, UnitPoint}, style.Border[EdgeRight])
+	assert.Equal(t, Value{3, UnitPoint}, style.Border[EdgeBottom])
+	assert.Equal(t, Value{4, UnitPoint}, style.Border[EdgeLeft])
+
+	style, err = ParseStyle("margin-left: auto; padding-start: 3px; border-bottom-width: 2px; border-top: 1px")
+	assert.NoError(t, err)
+	assert.Equal(t, UnitAuto, style.Margin[EdgeLeft].Unit)
+	assert.Equal(t, Value{3, UnitPoint}, style.Padding[EdgeStart])
+	assert.Equal(t, Value{2, UnitPoint}, style.Border[EdgeBottom])
+	assert.Equal(t, Value{1, UnitPoint}, style.Border[EdgeTop])
+
+	style, err = ParseStyle("left: 10%; end: 2px; inset-vertical: 3px")
+	assert.NoError(t, err)
+	assert.Equal(t, Value{10, UnitPercent}, style.Position[EdgeLeft])
+	assert.Equal(t, Value{2, UnitPoint}, style.Position[EdgeEnd])
+	assert.Equal(t, Value{3, UnitPoint}, style.Position[EdgeVertical])
+
+	// shorthand overrides edges set before it
+	style, err = ParseStyle("margin-left: 10px; margin: 4px")
+	assert.NoError(t, err)
+	assert.Equal(t, UnitUndefined, style.Margin[EdgeLeft].Unit)
+	assert.Equal(t, Value{4, UnitPoint}, style.Margin[EdgeAll])
+}
+
+func TestParse_style_flex(t *testing.T) {
+	tests := []struct {
+		css    string
+		grow   float32
+		shrink float32
+		basis  Value
+	}{
+		{"flex: 2", 2, 1, Value{0, UnitPoint}},
+		{"flex: 0", 0, 1, Value{0, UnitPoint}},
+		{"flex: none", 0, 0, autoValue},
+		{"flex: auto", 1, 1, autoValue},
+		{"flex: 10px", 1, 1, Value{10, UnitPoint}},
+		{"flex: 2 3", 2, 3, Value{0, UnitPoint}},
+		{"flex: 2 30%", 2, 1, Value{30, UnitPercent}},
+		{"flex: 2 3 auto", 2, 3, autoValue},
+		{"flex: 10px 2 3", 2, 3, Value{10, UnitPoint}},
+	}
+	for _, test := range tests {
+		style, err := ParseStyle(test.css)
+		assert.NoError(t, err, test.css)
+		assert.True(t, FloatIsUndefined(style.Flex), test.css)
+		assert.Equal(t, test.grow, style.FlexGrow, test.css)
+		assert.Equal(t, test.shrink, style.FlexShrink, test.css)
+		assert.True(t, valueEq(test.basis, style.FlexBasis), test.css)
+	}
+}
+
+func TestParse_style_misc(t *testing.T) {
+	style, err := ParseStyle("flex-flow: row wrap; gap: 4px; aspect-ratio: 16 / 9; flex-grow: 1.5; flex-shrink: 0")
+	assert.NoError(t, err)
+	assert.Equal(t, FlexDirectionRow, style.FlexDirection)
+	assert.Equal(t, WrapWrap, style.FlexWrap)
+	assert.Equal(t, Value{4, UnitPoint}, style.Gap[GutterAll])
+	assertFloatEqual(t, 16.0/9.0, style.AspectRatio)
+	assertFloatEqual(t, 1.5, style.FlexGrow)
+	assertFloatEqual(t, 0, style.FlexShrink)
+
+	style, err = ParseStyle("flex-flow: wrap-reverse; gap: 1px 2%; aspect-ratio: 2/1")
+	assert.NoError(t, err)
+	assert.Equal(t, FlexDirectionColumn, style.FlexDirection)
+	assert.Equal(t, WrapWrapReverse, style.FlexWrap)
+	assert.Equal(t, UnitUndefined, style.Gap[GutterAll].Unit)
+	assert.Equal(t, Value{1, UnitPoint}, style.Gap[GutterRow])
+	assert.Equal(t, Value{2, UnitPercent}, style.Gap[GutterColumn])
+	assertFloatEqual(t, 2, style.AspectRatio)
+
+	style, err = ParseStyle("row-gap: 3px; column-gap: 5%")
+	assert.NoError(t, err)
+	assert.Equal(t, Value{3, UnitPoint}, style.Gap[GutterRow])
+	assert.Equal(t, Value{5, UnitPercent}, style.Gap[GutterColumn])
+}
+
+func TestParse_style_errors(t *testing.T) {
+	tests := []struct {
+		css    string
+		line   int
+		column int
+		msg    string
+	}{
+		{"width 10px", 1, 1, "expected ':' after property name"},
+		{"width: 10px;\n  colour: red", 2, 3, `unknown property "colour"`},
+		{"width:", 1, 7, "missing value for width"},
+		{"width: 10em", 1, 8, `invalid length "10em"`},
+		{"width: -10px", 1, 8, `negative value "-10px"`},
+		{"flex-direction: sideways", 1, 17, `invalid value "sideways"`},
+		{"border: 10%", 1, 9, `percent value "10%" not allowed`},
+		{"margin: 1px 2px 3px 4px 5px", 1, 25, `unexpected "5px"`},
+		{"height: 1px 2px", 1, 13, `unexpected "2px"`},
+		{"flex: 1 10px 2", 1, 14, `unexpected "2"`},
+		{"aspect-ratio: 1 / 0", 1, 19, "invalid aspect ratio"},
+		{"/* width: 10px", 1, 1, "unterminated comment"},
+		{": 10px", 1, 1, "missing property name"},
+	}
+	for _, test := range tests {
+		_, err := ParseStyle(test.css)
+		if assert.Error(t, err, test.css) {
+			perr, ok := err.(*StyleParseError)
+			assert.True(t, ok, test.css)
+			assert.Equal(t, test.line, perr.Line, test.css)
+			assert.Equal(t, test.column, perr.Column, test.css)
+			assert.Equal(t, test.msg, perr.Msg, test.css)
+		}
+	}
+
+	_, err := ParseStyle("width: 10px;\n  colour: red")
+	assert.Equal(t, `2:3: unknown property "colour"`, err.Error())
+	assert.Equal(t, 15, err.(*StyleParseError).Offset)
+}
+
+func TestApply_style(t *testing.T) {
+	root := NewNode()
+	assert.NoError(t, root.ApplyStyle("flex-direction: row; width: 100px; height: 100px; padding: 10px"))
+
+	rootChild0 := NewNode()
+	assert.NoError(t, rootChild0.ApplyStyle("flex: 1; margin: 0 5px"))
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNode()
+	assert.NoError(t, rootChild1.ApplyStyle("width: 20%"))
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 15, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 10, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 54, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 80, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 74, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 16, rootChild1.LayoutGetWidth())
+
+	// unchanged style doesn't make the node dirty
+	assert.NoError(t, rootChild1.ApplyStyle("width: 20%"))
+	assert.False(t, root.IsDirty)
+
+	assert.NoError(t, rootChild1.ApplyStyle("aspect-ratio: 2"))
+	assert.True(t, root.IsDirty)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.False(t, root.IsDirty)
+
+	// failed declarations leave the node unchanged
+	err := rootChild1.ApplyStyle("width: 30%; height: big")
+	assert.Error(t, err)
+	assert.Equal(t, Value{20, UnitPercent}, rootChild1.Style.Dimensions[DimensionWidth])
+	assert.False(t, root.IsDirty)
+}
@@ -130,3 +130,108 @@ func TestDirty_node_only_if_children_are_actually_removed(t *testing.T) {
 	root.RemoveChild(child0)
 	assert.True(t, root.IsDirty)
 }
+
+func TestDirty_detect_style_changes(t *testing.T) {
+	logger := &testLogger{}
+	config := NewConfig()
+	config.Logger = logger.log
+	config.DetectStyleChanges = true
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild1, 1)
+
+	stats := CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 0, stats.StyleChanges)
+	assert.Equal(t, 0, len(logger.entries))
+
+	rootChild0.Style.Dimensions[DimensionWidth] = Value{Value: 40, Unit: UnitPoint}
+	assert.False(t, root.IsDirty)
+
+	stats = CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 1, stats.StyleChanges)
+	assert.Equal(t, 1, len(logger.entries))
+	assert.Equal(t, LogLevelWarn, logger.entries[0].level)
+	assert.Equal(t, rootChild0, logger.entries[0].node)
+	assertFloatEqual(t, 40, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 60, rootChild1.LayoutGetWidth())
+
+	// change marked dirty by a setter isn't reported
+	rootChild0.StyleSetWidth(50)
+	stats = CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 0, stats.StyleChanges)
+	assertFloatEqual(t, 50, rootChild1.LayoutGetLeft())
+
+	stats = CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 0, stats.StyleChanges)
+	assert.Equal(t, 1, len(logger.entries))
+}
+
+func TestDirty_detect_grid_track_changes(t *testing.T) {
+	config := NewConfig()
+	config.DetectStyleChanges = true
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayGrid)
+	root.StyleSetGridTemplateColumns(GridTrackPoints(30), GridTrackFr(1))
+	root.StyleSetWidth(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	root.InsertChild(rootChild0, 0)
+	rootChild1 := NewNodeWithConfig(config)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 30, rootChild1.LayoutGetLeft())
+
+	root.Style.GridTemplateColumns[0] = GridTrackPoints(40)
+	stats := CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 1, stats.StyleChanges)
+	assertFloatEqual(t, 40, rootChild1.LayoutGetLeft())
+}
+
+func TestDirty_dirtied_callback(t *testing.T) {
+	var dirtied []*Node
+	onDirtied := func(node *Node) {
+		dirtied = append(dirtied, node)
+	}
+
+	root := NewNode()
+	root.StyleSetAlignItems(AlignFlexStart)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	root.Dirtied = onDirtied
+
+	rootChild0 := NewNode()
+	rootChild0.SetMeasureFunc(_measure3)
+	rootChild0.Dirtied = onDirtied
+	root.InsertChild(rootChild0, 0)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	dirtied = nil
+
+	rootChild0.MarkDirty()
+	assert.Equal(t, 2, len(dirtied))
+	assert.Same(t, rootChild0, dirtied[0])
+	assert.Same(t, root, dirtied[1])
+
+	// already dirty
+	rootChild0.MarkDirty()
+	root.StyleSetWidth(50)
+	assert.Equal(t, 2, len(dirtied))
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	dirtied = nil
+
+	root.StyleSetWidth(60)
+	assert.Equal(t, 1, len(dirtied))
+	assert.Same(t, root, dirtied[0])
+}
@@ -1,6 +1,10 @@
 package flex
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func TestDisplay_none(t *testing.T) {
 	config := NewConfig()
@@ -302,3 +306,169 @@ func TestDisplay_none_with_position(t *testing.T) {
 	assertFloatEqual(t, 0, rootChild1.LayoutGetWidth())
 	assertFloatEqual(t, 0, rootChild1.LayoutGetHeight())
 }
+
+func TestDisplay_contents(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetDisplay(DisplayContents)
+	rootChild0.StyleSetPadding(EdgeAll, 10)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetFlexGrow(1)
+	rootChild0Child0.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild0Child1 := NewNodeWithConfig(config)
+	rootChild0Child1.StyleSetFlexGrow(1)
+	rootChild0Child1.StyleSetHeight(20)
+	rootChild0.InsertChild(rootChild0Child1, 1)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(20)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0Child0.LayoutGetTop())
+	assertFloatEqual(t, 40, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0Child0.LayoutGetHeight())
+
+	assertFloatEqual(t, 40, rootChild0Child1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0Child1.LayoutGetTop())
+	assertFloatEqual(t, 40, rootChild0Child1.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0Child1.LayoutGetHeight())
+
+	assertFloatEqual(t, 80, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild1.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0Child0.LayoutGetTop())
+	assertFloatEqual(t, 40, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild0Child0.LayoutGetHeight())
+
+	assertFloatEqual(t, 20, rootChild0Child1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0Child1.LayoutGetTop())
+	assertFloatEqual(t, 40, rootChild0Child1.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0Child1.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild1.LayoutGetHeight())
+}
+
+func TestDisplay_contents_nested(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetDisplay(DisplayContents)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetDisplay(DisplayContents)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild0Child0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0Child0.StyleSetFlexGrow(1)
+	rootChild0Child0.InsertChild(rootChild0Child0Child0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetHeight(30)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetHeight())
+	assertFloatEqual(t, 0, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 0, rootChild0Child0.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0Child0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0Child0Child0.LayoutGetTop())
+	assertFloatEqual(t, 100, rootChild0Child0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 70, rootChild0Child0Child0.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 70, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 100, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetHeight())
+}
+
+func TestDisplay_contents_relayout(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(50)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetFlexGrow(1)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 50, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 50, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetLeft())
+
+	rootChild0.StyleSetDisplay(DisplayContents)
+	assert.True(t, root.IsDirty)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 0, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 50, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild0Child0.LayoutGetHeight())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetWidth())
+
+	rootChild0Child0.StyleSetFlexGrow(3)
+	assert.True(t, root.IsDirty)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 75, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 75, rootChild1.LayoutGetLeft())
+	assert.False(t, rootChild0.IsDirty)
+}
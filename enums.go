@@ -22,6 +22,8 @@ const (
 	AlignSpaceBetween
 	// AlignSpaceAround is "space-around"
 	AlignSpaceAround
+	// AlignSpaceEvenly is "space-evenly"
+	AlignSpaceEvenly
 )
 
 // Dimension represents dimention
@@ -54,6 +56,14 @@ const (
 	DisplayFlex Display = iota
 	// DisplayNone is "none"
 	DisplayNone
+	// DisplayContents is "contents"
+	DisplayContents
+	// DisplayGrid is "grid"
+	DisplayGrid
+	// DisplayBlock is "block"
+	DisplayBlock
+	// DisplayInlineBlock is "inline-block"
+	DisplayInlineBlock
 )
 
 // Edge represents an edge
@@ -97,6 +107,22 @@ const (
 	experimentalFeatureCount = 1
 )
 
+// Gutter selects which gap a "gap" property applies to
+type Gutter int
+
+const (
+	// GutterColumn is "column-gap"
+	GutterColumn Gutter = iota
+	// GutterRow is "row-gap"
+	GutterRow
+	// GutterAll is "gap"
+	GutterAll
+)
+
+const (
+	gutterCount = 3
+)
+
 // FlexDirection describes "flex-direction" property
 type FlexDirection int
 
@@ -125,6 +151,8 @@ const (
 	JustifySpaceBetween
 	// JustifySpaceAround is "space-around"
 	JustifySpaceAround
+	// JustifySpaceEvenly is "space-evenly"
+	JustifySpaceEvenly
 )
 
 // LogLevel represents log level
@@ -185,6 +213,9 @@ const (
 	PositionTypeRelative PositionType = iota
 	// PositionTypeAbsolute is "absolute"
 	PositionTypeAbsolute
+	// PositionTypeStatic is "static". Insets are ignored and the node is not
+	// a containing block of its absolute descendants
+	PositionTypeStatic
 )
 
 type PrintOptions int
@@ -240,6 +271,8 @@ func AlignToString(value Align) string {
 		return "space-between"
 	case AlignSpaceAround:
 		return "space-around"
+	case AlignSpaceEvenly:
+		return "space-evenly"
 	}
 	return "unknown"
 }
@@ -275,6 +308,14 @@ func DisplayToString(value Display) string {
 		return "flex"
 	case DisplayNone:
 		return "none"
+	case DisplayContents:
+		return "contents"
+	case DisplayGrid:
+		return "grid"
+	case DisplayBlock:
+		return "block"
+	case DisplayInlineBlock:
+		return "inline-block"
 	}
 	return "unknown"
 }
@@ -328,6 +369,19 @@ func FlexDirectionToString(value FlexDirection) string {
 	return "unknown"
 }
 
+// GutterToString returns string version of Gutter enum
+func GutterToString(value Gutter) string {
+	switch value {
+	case GutterColumn:
+		return "column"
+	case GutterRow:
+		return "row"
+	case GutterAll:
+		return "all"
+	}
+	return "unknown"
+}
+
 // JustifyToString returns string version of Justify enum
 func JustifyToString(value Justify) string {
 	switch value {
@@ -341,6 +395,8 @@ func JustifyToString(value Justify) string {
 		return "space-between"
 	case JustifySpaceAround:
 		return "space-around"
+	case JustifySpaceEvenly:
+		return "space-evenly"
 	}
 	return "unknown"
 }
@@ -408,6 +464,8 @@ func PositionTypeToString(value PositionType) string {
 		return "relative"
 	case PositionTypeAbsolute:
 		return "absolute"
+	case PositionTypeStatic:
+		return "static"
 	}
 	return "unknown"
 }
@@ -0,0 +1,652 @@
+package flex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse* functions are the reverse of *ToString functions. Together with
+// MarshalText and UnmarshalText they allow enums to round-trip through
+// encoding/json, flag.TextVar and other packages using encoding.TextMarshaler
+
+// ParseAlign returns Align enum for a string returned by AlignToString
+func ParseAlign(s string) (Align, error) {
+	switch s {
+	case "auto":
+		return AlignAuto, nil
+	case "flex-start":
+		return AlignFlexStart, nil
+	case "center":
+		return AlignCenter, nil
+	case "flex-end":
+		return AlignFlexEnd, nil
+	case "stretch":
+		return AlignStretch, nil
+	case "baseline":
+		return AlignBaseline, nil
+	case "space-between":
+		return AlignSpaceBetween, nil
+	case "space-around":
+		return AlignSpaceAround, nil
+	case "space-evenly":
+		return AlignSpaceEvenly, nil
+	}
+	return Align(-1), fmt.Errorf("%w: %q is not an Align", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as AlignToString
+func (value Align) MarshalText() ([]byte, error) {
+	s := AlignToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not an Align", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseAlign
+func (value *Align) UnmarshalText(text []byte) error {
+	v, err := ParseAlign(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseDimension returns Dimension enum for a string returned by DimensionToString
+func ParseDimension(s string) (Dimension, error) {
+	switch s {
+	case "width":
+		return DimensionWidth, nil
+	case "height":
+		return DimensionHeight, nil
+	}
+	return Dimension(-1), fmt.Errorf("%w: %q is not a Dimension", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as DimensionToString
+func (value Dimension) MarshalText() ([]byte, error) {
+	s := DimensionToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a Dimension", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseDimension
+func (value *Dimension) UnmarshalText(text []byte) error {
+	v, err := ParseDimension(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseDirection returns Direction enum for a string returned by DirectionToString
+func ParseDirection(s string) (Direction, error) {
+	switch s {
+	case "inherit":
+		return DirectionInherit, nil
+	case "ltr":
+		return DirectionLTR, nil
+	case "rtl":
+		return DirectionRTL, nil
+	}
+	return Direction(-1), fmt.Errorf("%w: %q is not a Direction", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as DirectionToString
+func (value Direction) MarshalText() ([]byte, error) {
+	s := DirectionToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a Direction", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseDirection
+func (value *Direction) UnmarshalText(text []byte) error {
+	v, err := ParseDirection(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseDisplay returns Display enum for a string returned by DisplayToString
+func ParseDisplay(s string) (Display, error) {
+	switch s {
+	case "flex":
+		return DisplayFlex, nil
+	case "none":
+		return DisplayNone, nil
+	case "contents":
+		return DisplayContents, nil
+	case "grid":
+		return DisplayGrid, nil
+	case "block":
+		return DisplayBlock, nil
+	case "inline-block":
+		return DisplayInlineBlock, nil
+	}
+	return Display(-1), fmt.Errorf("%w: %q is not a Display", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as DisplayToString
+func (value Display) MarshalText() ([]byte, error) {
+	s := DisplayToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a Display", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseDisplay
+func (value *Display) UnmarshalText(text []byte) error {
+	v, err := ParseDisplay(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseEdge returns Edge enum for a string returned by EdgeToString
+func ParseEdge(s string) (Edge, error) {
+	switch s {
+	case "left":
+		return EdgeLeft, nil
+	case "top":
+		return EdgeTop, nil
+	case "right":
+		return EdgeRight, nil
+	case "bottom":
+		return EdgeBottom, nil
+	case "start":
+		return EdgeStart, nil
+	case "end":
+		return EdgeEnd, nil
+	case "horizontal":
+		return EdgeHorizontal, nil
+	case "vertical":
+		return EdgeVertical, nil
+	case "all":
+		return EdgeAll, nil
+	}
+	return Edge(-1), fmt.Errorf("%w: %q is not an Edge", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as EdgeToString
+func (value Edge) MarshalText() ([]byte, error) {
+	s := EdgeToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not an Edge", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseEdge
+func (value *Edge) UnmarshalText(text []byte) error {
+	v, err := ParseEdge(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseExperimentalFeature returns ExperimentalFeature enum for a string returned by ExperimentalFeatureToString
+func ParseExperimentalFeature(s string) (ExperimentalFeature, error) {
+	switch s {
+	case "web-flex-basis":
+		return ExperimentalFeatureWebFlexBasis, nil
+	}
+	return ExperimentalFeature(-1), fmt.Errorf("%w: %q is not an ExperimentalFeature", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as ExperimentalFeatureToString
+func (value ExperimentalFeature) MarshalText() ([]byte, error) {
+	s := ExperimentalFeatureToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not an ExperimentalFeature", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseExperimentalFeature
+func (value *ExperimentalFeature) UnmarshalText(text []byte) error {
+	v, err := ParseExperimentalFeature(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseFlexDirection returns FlexDirection enum for a string returned by FlexDirectionToString
+func ParseFlexDirection(s string) (FlexDirection, error) {
+	switch s {
+	case "column":
+		return FlexDirectionColumn, nil
+	case "column-reverse":
+		return FlexDirectionColumnReverse, nil
+	case "row":
+		return FlexDirectionRow, nil
+	case "row-reverse":
+		return FlexDirectionRowReverse, nil
+	}
+	return FlexDirection(-1), fmt.Errorf("%w: %q is not a FlexDirection", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as FlexDirectionToString
+func (value FlexDirection) MarshalText() ([]byte, error) {
+	s := FlexDirectionToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a FlexDirection", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseFlexDirection
+func (value *FlexDirection) UnmarshalText(text []byte) error {
+	v, err := ParseFlexDirection(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseGutter returns Gutter enum for a string returned by GutterToString
+func ParseGutter(s string) (Gutter, error) {
+	switch s {
+	case "column":
+		return GutterColumn, nil
+	case "row":
+		return GutterRow, nil
+	case "all":
+		return GutterAll, nil
+	}
+	return Gutter(-1), fmt.Errorf("%w: %q is not a Gutter", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as GutterToString
+func (value Gutter) MarshalText() ([]byte, error) {
+	s := GutterToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a Gutter", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseGutter
+func (value *Gutter) UnmarshalText(text []byte) error {
+	v, err := ParseGutter(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseJustify returns Justify enum for a string returned by JustifyToString
+func ParseJustify(s string) (Justify, error) {
+	switch s {
+	case "flex-start":
+		return JustifyFlexStart, nil
+	case "center":
+		return JustifyCenter, nil
+	case "flex-end":
+		return JustifyFlexEnd, nil
+	case "space-between":
+		return JustifySpaceBetween, nil
+	case "space-around":
+		return JustifySpaceAround, nil
+	case "space-evenly":
+		return JustifySpaceEvenly, nil
+	}
+	return Justify(-1), fmt.Errorf("%w: %q is not a Justify", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as JustifyToString
+func (value Justify) MarshalText() ([]byte, error) {
+	s := JustifyToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a Justify", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseJustify
+func (value *Justify) UnmarshalText(text []byte) error {
+	v, err := ParseJustify(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseLogLevel returns LogLevel enum for a string returned by LogLevelToString
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch s {
+	case "error":
+		return LogLevelError, nil
+	case "warn":
+		return LogLevelWarn, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "debug":
+		return LogLevelDebug, nil
+	case "verbose":
+		return LogLevelVerbose, nil
+	case "fatal":
+		return LogLevelFatal, nil
+	}
+	return LogLevel(-1), fmt.Errorf("%w: %q is not a LogLevel", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as LogLevelToString
+func (value LogLevel) MarshalText() ([]byte, error) {
+	s := LogLevelToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a LogLevel", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseLogLevel
+func (value *LogLevel) UnmarshalText(text []byte) error {
+	v, err := ParseLogLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseMeasureMode returns MeasureMode enum for a string returned by MeasureModeToString
+func ParseMeasureMode(s string) (MeasureMode, error) {
+	switch s {
+	case "undefined":
+		return MeasureModeUndefined, nil
+	case "exactly":
+		return MeasureModeExactly, nil
+	case "at-most":
+		return MeasureModeAtMost, nil
+	}
+	return MeasureMode(-1), fmt.Errorf("%w: %q is not a MeasureMode", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as MeasureModeToString
+func (value MeasureMode) MarshalText() ([]byte, error) {
+	s := MeasureModeToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a MeasureMode", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseMeasureMode
+func (value *MeasureMode) UnmarshalText(text []byte) error {
+	v, err := ParseMeasureMode(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseNodeType returns NodeType enum for a string returned by NodeTypeToString
+func ParseNodeType(s string) (NodeType, error) {
+	switch s {
+	case "default":
+		return NodeTypeDefault, nil
+	case "text":
+		return NodeTypeText, nil
+	}
+	return NodeType(-1), fmt.Errorf("%w: %q is not a NodeType", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as NodeTypeToString
+func (value NodeType) MarshalText() ([]byte, error) {
+	s := NodeTypeToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a NodeType", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseNodeType
+func (value *NodeType) UnmarshalText(text []byte) error {
+	v, err := ParseNodeType(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseOverflow returns Overflow enum for a string returned by OverflowToString
+func ParseOverflow(s string) (Overflow, error) {
+	switch s {
+	case "visible":
+		return OverflowVisible, nil
+	case "hidden":
+		return OverflowHidden, nil
+	case "scroll":
+		return OverflowScroll, nil
+	}
+	return Overflow(-1), fmt.Errorf("%w: %q is not an Overflow", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as OverflowToString
+func (value Overflow) MarshalText() ([]byte, error) {
+	s := OverflowToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not an Overflow", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseOverflow
+func (value *Overflow) UnmarshalText(text []byte) error {
+	v, err := ParseOverflow(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParsePositionType returns PositionType enum for a string returned by PositionTypeToString
+func ParsePositionType(s string) (PositionType, error) {
+	switch s {
+	case "relative":
+		return PositionTypeRelative, nil
+	case "absolute":
+		return PositionTypeAbsolute, nil
+	case "static":
+		return PositionTypeStatic, nil
+	}
+	return PositionType(-1), fmt.Errorf("%w: %q is not a PositionType", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as PositionTypeToString
+func (value PositionType) MarshalText() ([]byte, error) {
+	s := PositionTypeToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a PositionType", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParsePositionType
+func (value *PositionType) UnmarshalText(text []byte) error {
+	v, err := ParsePositionType(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// printOptionsFlags are PrintOptions in the order they're marshaled
+var printOptionsFlags = []PrintOptions{PrintOptionsLayout, PrintOptionsStyle, PrintOptionsChildren}
+
+// ParsePrintOptions returns PrintOptions for a string returned by
+// PrintOptions.MarshalText: names of flags returned by PrintOptionsToString
+// separated by "|". "" and "none" are no flags
+func ParsePrintOptions(s string) (PrintOptions, error) {
+	if s == "" || s == "none" {
+		return 0, nil
+	}
+	var value PrintOptions
+	for _, name := range strings.Split(s, "|") {
+		switch strings.TrimSpace(name) {
+		case "layout":
+			value |= PrintOptionsLayout
+		case "style":
+			value |= PrintOptionsStyle
+		case "children":
+			value |= PrintOptionsChildren
+		default:
+			return PrintOptions(-1), fmt.Errorf("%w: %q is not a PrintOptions", ErrInvalidEnumValue, s)
+		}
+	}
+	return value, nil
+}
+
+// MarshalText returns names of set flags separated by "|", "none" if no flag
+// is set
+func (value PrintOptions) MarshalText() ([]byte, error) {
+	if value == 0 {
+		return []byte("none"), nil
+	}
+	var names []string
+	rest := value
+	for _, flag := range printOptionsFlags {
+		if value&flag != 0 {
+			names = append(names, PrintOptionsToString(flag))
+			rest &^= flag
+		}
+	}
+	if rest != 0 {
+		return nil, fmt.Errorf("%w: %d is not a PrintOptions", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(strings.Join(names, "|")), nil
+}
+
+// UnmarshalText parses text with ParsePrintOptions
+func (value *PrintOptions) UnmarshalText(text []byte) error {
+	v, err := ParsePrintOptions(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseUnit returns Unit enum for a string returned by UnitToString
+func ParseUnit(s string) (Unit, error) {
+	switch s {
+	case "undefined":
+		return UnitUndefined, nil
+	case "point":
+		return UnitPoint, nil
+	case "percent":
+		return UnitPercent, nil
+	case "auto":
+		return UnitAuto, nil
+	}
+	return Unit(-1), fmt.Errorf("%w: %q is not a Unit", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as UnitToString
+func (value Unit) MarshalText() ([]byte, error) {
+	s := UnitToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a Unit", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseUnit
+func (value *Unit) UnmarshalText(text []byte) error {
+	v, err := ParseUnit(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseWrap returns Wrap enum for a string returned by WrapToString
+func ParseWrap(s string) (Wrap, error) {
+	switch s {
+	case "no-wrap":
+		return WrapNoWrap, nil
+	case "wrap":
+		return WrapWrap, nil
+	case "wrap-reverse":
+		return WrapWrapReverse, nil
+	}
+	return Wrap(-1), fmt.Errorf("%w: %q is not a Wrap", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as WrapToString
+func (value Wrap) MarshalText() ([]byte, error) {
+	s := WrapToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a Wrap", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseWrap
+func (value *Wrap) UnmarshalText(text []byte) error {
+	v, err := ParseWrap(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
+
+// ParseLayoutEventType returns LayoutEventType enum for a string returned by LayoutEventTypeToString
+func ParseLayoutEventType(s string) (LayoutEventType, error) {
+	switch s {
+	case "enter":
+		return LayoutEventEnter, nil
+	case "exit":
+		return LayoutEventExit, nil
+	case "cache-hit":
+		return LayoutEventCacheHit, nil
+	case "cache-overflow":
+		return LayoutEventCacheOverflow, nil
+	case "done":
+		return LayoutEventDone, nil
+	}
+	return LayoutEventType(-1), fmt.Errorf("%w: %q is not a LayoutEventType", ErrInvalidEnumValue, s)
+}
+
+// MarshalText returns the same text as LayoutEventTypeToString
+func (value LayoutEventType) MarshalText() ([]byte, error) {
+	s := LayoutEventTypeToString(value)
+	if s == "unknown" {
+		return nil, fmt.Errorf("%w: %d is not a LayoutEventType", ErrInvalidEnumValue, int(value))
+	}
+	return []byte(s), nil
+}
+
+// UnmarshalText parses text with ParseLayoutEventType
+func (value *LayoutEventType) UnmarshalText(text []byte) error {
+	v, err := ParseLayoutEventType(string(text))
+	if err != nil {
+		return err
+	}
+	*value = v
+	return nil
+}
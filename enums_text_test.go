@@ -0,0 +1,152 @@
+package flex
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_enums_round_trip(t *testing.T) {
+	for v := AlignAuto; v <= AlignSpaceEvenly; v++ {
+		got, err := ParseAlign(AlignToString(v))
+		assert.NoError(t, err)
+		assert.Equal(t, v, got)
+	}
+	for v := EdgeLeft; v <= EdgeAll; v++ {
+		got, err := ParseEdge(EdgeToString(v))
+		assert.NoError(t, err)
+		assert.Equal(t, v, got)
+	}
+	for v := JustifyFlexStart; v <= JustifySpaceEvenly; v++ {
+		got, err := ParseJustify(JustifyToString(v))
+		assert.NoError(t, err)
+		assert.Equal(t, v, got)
+	}
+	for v := WrapNoWrap; v <= WrapWrapReverse; v++ {
+		got, err := ParseWrap(WrapToString(v))
+		assert.NoError(t, err)
+		assert.Equal(t, v, got)
+	}
+	for _, v := range []PrintOptions{PrintOptionsLayout, PrintOptionsStyle, PrintOptionsChildren} {
+		got, err := ParsePrintOptions(PrintOptionsToString(v))
+		assert.NoError(t, err)
+		assert.Equal(t, v, got)
+	}
+
+	for v := PrintOptions(0); v <= PrintOptionsLayout|PrintOptionsStyle|PrintOptionsChildren; v++ {
+		text, err := v.MarshalText()
+		assert.NoError(t, err)
+		var got PrintOptions
+		assert.NoError(t, got.UnmarshalText(text))
+		assert.Equal(t, v, got)
+	}
+
+	d, err := ParseDirection("rtl")
+	assert.NoError(t, err)
+	assert.Equal(t, DirectionRTL, d)
+	fd, err := ParseFlexDirection("row-reverse")
+	assert.NoError(t, err)
+	assert.Equal(t, FlexDirectionRowReverse, fd)
+	u, err := ParseUnit("percent")
+	assert.NoError(t, err)
+	assert.Equal(t, UnitPercent, u)
+	l, err := ParseLogLevel("verbose")
+	assert.NoError(t, err)
+	assert.Equal(t, LogLevelVerbose, l)
+	e, err := ParseLayoutEventType("cache-hit")
+	assert.NoError(t, err)
+	assert.Equal(t, LayoutEventCacheHit, e)
+}
+
+func TestParse_enums_invalid(t *testing.T) {
+	_, err := ParseAlign("strech")
+	assert.True(t, errors.Is(err, ErrInvalidEnumValue))
+	assert.Equal(t, `invalid enum value: "strech" is not an Align`, err.Error())
+
+	_, err = ParseWrap("nowrap")
+	assert.True(t, errors.Is(err, ErrInvalidEnumValue))
+
+	_, err = ParseDisplay("")
+	assert.True(t, errors.Is(err, ErrInvalidEnumValue))
+
+	_, err = Justify(42).MarshalText()
+	assert.True(t, errors.Is(err, ErrInvalidEnumValue))
+	assert.Equal(t, "invalid enum value: 42 is not a Justify", err.Error())
+
+	v := OverflowScroll
+	err = v.UnmarshalText([]byte("clip"))
+	assert.Error(t, err)
+	assert.Equal(t, OverflowScroll, v)
+}
+
+func TestEnums_json(t *testing.T) {
+	type props struct {
+		Direction FlexDirection
+		Align     Align
+		Edges     map[Edge]float32
+	}
+	in := props{
+		Direction: FlexDirectionRow,
+		Align:     AlignSpaceEvenly,
+		Edges:     map[Edge]float32{EdgeLeft: 1, EdgeAll: 2},
+	}
+	d, err := json.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Direction":"row","Align":"space-evenly","Edges":{"all":2,"left":1}}`, string(d))
+
+	var out props
+	assert.NoError(t, json.Unmarshal(d, &out))
+	assert.Equal(t, in, out)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"Align":"middle"}`), &out))
+}
+
+func TestEnums_flag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var justify Justify
+	fs.TextVar(&justify, "justify", JustifyFlexStart, "justify-content")
+	assert.NoError(t, fs.Parse([]string{"-justify", "center"}))
+	assert.Equal(t, JustifyCenter, justify)
+}
+
+func TestEnums_print_options_flags(t *testing.T) {
+	all := PrintOptionsLayout | PrintOptionsStyle | PrintOptionsChildren
+	text, err := all.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "layout|style|children", string(text))
+
+	text, err = PrintOptions(0).MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "none", string(text))
+
+	v, err := ParsePrintOptions("children | layout")
+	assert.NoError(t, err)
+	assert.Equal(t, PrintOptionsLayout|PrintOptionsChildren, v)
+	v, err = ParsePrintOptions("")
+	assert.NoError(t, err)
+	assert.Equal(t, PrintOptions(0), v)
+
+	_, err = ParsePrintOptions("layout|colors")
+	assert.True(t, errors.Is(err, ErrInvalidEnumValue))
+	_, err = PrintOptions(8).MarshalText()
+	assert.True(t, errors.Is(err, ErrInvalidEnumValue))
+
+	type options struct {
+		Print PrintOptions
+	}
+	d, err := json.Marshal(options{Print: PrintOptionsLayout | PrintOptionsStyle})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Print":"layout|style"}`, string(d))
+	var out options
+	assert.NoError(t, json.Unmarshal(d, &out))
+	assert.Equal(t, PrintOptionsLayout|PrintOptionsStyle, out.Print)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var print PrintOptions
+	fs.TextVar(&print, "print", PrintOptionsLayout, "print options")
+	assert.NoError(t, fs.Parse([]string{"-print", "style|children"}))
+	assert.Equal(t, PrintOptionsStyle|PrintOptionsChildren, print)
+}
@@ -0,0 +1,52 @@
+package flex
+
+import "errors"
+
+// Errors returned by Try* variants of functions that otherwise panic
+// when misused
+var (
+	// ErrChildHasParent is returned when inserting a child that is already
+	// attached to another node
+	ErrChildHasParent = errors.New("Child already has a parent, it must be removed first.")
+	// ErrMeasuredNodeCannotHaveChildren is returned when inserting a child
+	// into a node with a measure function
+	ErrMeasuredNodeCannotHaveChildren = errors.New("Cannot add child: Nodes with measure functions cannot have children.")
+	// ErrMeasuredNodeHasChildren is returned when setting a measure function
+	// on a node that has children
+	ErrMeasuredNodeHasChildren = errors.New("Cannot set measure function: Nodes with measure functions cannot have children.")
+	// ErrChildIndexOutOfRange is returned when inserting a child at an index
+	// past the end of children
+	ErrChildIndexOutOfRange = errors.New("Cannot add child: index out of range")
+	// ErrResetNodeHasChildren is returned when resetting a node with children
+	ErrResetNodeHasChildren = errors.New("Cannot reset a node which still has children attached")
+	// ErrResetNodeHasParent is returned when resetting a node attached to a parent
+	ErrResetNodeHasParent = errors.New("Cannot reset a node still attached to a parent")
+	// ErrMarkDirtyWithoutMeasureFunc is returned when marking dirty a node
+	// without a measure function
+	ErrMarkDirtyWithoutMeasureFunc = errors.New("Only leaf nodes with custom measure functions should manually mark themselves as dirty")
+	// ErrNegativePointScaleFactor is returned when setting a negative scale factor
+	ErrNegativePointScaleFactor = errors.New("Scale factor should not be less than zero")
+	// ErrVirtualListNodeCannotHaveChildren is returned when inserting a child
+	// into a node with a virtual list
+	ErrVirtualListNodeCannotHaveChildren = errors.New("Cannot add child: Children of nodes with virtual lists are created by the list.")
+	// ErrVirtualListNodeHasChildren is returned when setting a virtual list on
+	// a node that has children or a measure function
+	ErrVirtualListNodeHasChildren = errors.New("Cannot set virtual list: Node already has children or a measure function.")
+	// ErrVirtualListAttached is returned when setting a virtual list that's
+	// already set on another node
+	ErrVirtualListAttached = errors.New("Cannot set virtual list: List is already set on another node.")
+)
+
+// ErrInvalidEnumValue is wrapped by errors returned from Parse* functions
+// and from MarshalText and UnmarshalText of enums
+var ErrInvalidEnumValue = errors.New("invalid enum value")
+
+// Errors returned by MarshalNodeJSON and UnmarshalNodeJSON
+var (
+	// ErrMeasureFuncNotRegistered is returned when marshaling a node whose
+	// measure function wasn't set with MeasureFuncRegistry.SetMeasureFunc
+	ErrMeasureFuncNotRegistered = errors.New("measure function has no name, set it with MeasureFuncRegistry.SetMeasureFunc")
+	// ErrUnknownMeasureFunc is returned when a measure function name is not
+	// in MeasureFuncRegistry
+	ErrUnknownMeasureFunc = errors.New("unknown measure function")
+)
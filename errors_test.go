@@ -0,0 +1,129 @@
+package flex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func measureZero(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+	return Size{}
+}
+
+func TestTry_insert_child_with_parent(t *testing.T) {
+	root0 := NewNode()
+	root1 := NewNode()
+	child := NewNode()
+	assert.NoError(t, root0.TryInsertChild(child, 0))
+
+	err := root1.TryInsertChild(child, 0)
+	assert.True(t, errors.Is(err, ErrChildHasParent))
+	assert.Equal(t, 0, len(root1.Children))
+	assert.Equal(t, root0, child.Parent)
+}
+
+func TestTry_insert_child_into_measured_node(t *testing.T) {
+	root := NewNode()
+	root.SetMeasureFunc(measureZero)
+
+	err := root.TryInsertChild(NewNode(), 0)
+	assert.Equal(t, ErrMeasuredNodeCannotHaveChildren, err)
+	assert.Equal(t, 0, len(root.Children))
+}
+
+func TestTry_insert_child_index_out_of_range(t *testing.T) {
+	root := NewNode()
+	assert.Equal(t, ErrChildIndexOutOfRange, root.TryInsertChild(NewNode(), 1))
+	assert.Equal(t, ErrChildIndexOutOfRange, root.TryInsertChild(NewNode(), -1))
+	assert.Equal(t, 0, len(root.Children))
+}
+
+func TestTry_set_measure_func_with_children(t *testing.T) {
+	root := NewNode()
+	root.InsertChild(NewNode(), 0)
+
+	assert.Equal(t, ErrMeasuredNodeHasChildren, root.TrySetMeasureFunc(measureZero))
+	assert.Nil(t, root.Measure)
+	assert.Equal(t, NodeTypeDefault, root.NodeType)
+
+	// removing measure function is always allowed
+	assert.NoError(t, root.TrySetMeasureFunc(nil))
+}
+
+func TestTry_reset(t *testing.T) {
+	root := NewNode()
+	child := NewNode()
+	root.InsertChild(child, 0)
+
+	assert.Equal(t, ErrResetNodeHasChildren, root.TryReset())
+	assert.Equal(t, ErrResetNodeHasParent, child.TryReset())
+
+	root.RemoveChild(child)
+	child.StyleSetWidth(10)
+	assert.NoError(t, child.TryReset())
+	assert.Equal(t, UnitAuto, child.StyleGetWidth().Unit)
+}
+
+func TestTry_mark_dirty(t *testing.T) {
+	root := NewNode()
+	assert.Equal(t, ErrMarkDirtyWithoutMeasureFunc, root.TryMarkDirty())
+	assert.False(t, root.IsDirty)
+
+	root.SetMeasureFunc(measureZero)
+	assert.NoError(t, root.TryMarkDirty())
+	assert.True(t, root.IsDirty)
+}
+
+func TestTry_set_point_scale_factor(t *testing.T) {
+	config := NewConfig()
+	assert.Equal(t, ErrNegativePointScaleFactor, config.TrySetPointScaleFactor(-1))
+	assertFloatEqual(t, 1, config.PointScaleFactor)
+	assert.NoError(t, config.TrySetPointScaleFactor(2))
+	assertFloatEqual(t, 2, config.PointScaleFactor)
+}
+
+func TestInsert_child_panics(t *testing.T) {
+	logger := &testLogger{}
+	config := NewConfig()
+	config.Logger = logger.log
+
+	root := NewNodeWithConfig(config)
+	root.SetMeasureFunc(measureZero)
+
+	assert.PanicsWithValue(t, ErrMeasuredNodeCannotHaveChildren.Error(), func() {
+		root.InsertChild(NewNodeWithConfig(config), 0)
+	})
+	assert.Equal(t, 1, len(logger.entries))
+	assert.Equal(t, LogLevelFatal, logger.entries[0].level)
+}
+
+func TestLog_assertions(t *testing.T) {
+	logger := &testLogger{}
+	config := NewConfig()
+	config.Logger = logger.log
+	config.LogAssertions = true
+
+	root := NewNodeWithConfig(config)
+	root.SetMeasureFunc(measureZero)
+	child := NewNodeWithConfig(config)
+
+	assert.NotPanics(t, func() {
+		root.InsertChild(child, 0)
+	})
+	assert.Equal(t, 0, len(root.Children))
+	assert.Nil(t, child.Parent)
+
+	assert.NotPanics(t, func() {
+		root.LayoutGetMargin(EdgeAll)
+		config.SetPointScaleFactor(-1)
+	})
+	assertFloatEqual(t, 1, config.PointScaleFactor)
+
+	assert.Equal(t, 3, len(logger.entries))
+	for _, e := range logger.entries {
+		assert.Equal(t, LogLevelError, e.level)
+	}
+	assert.Equal(t, ErrMeasuredNodeCannotHaveChildren.Error()+"\n", logger.entries[0].msg)
+	assert.Equal(t, root, logger.entries[0].node)
+}
@@ -0,0 +1,325 @@
+package flex
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumn_gap_flexible(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(80)
+	root.StyleSetHeight(100)
+	root.StyleSetGap(GutterColumn, 10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetFlexShrink(1)
+	rootChild0.StyleSetFlexBasisPercent(0)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFlexGrow(1)
+	rootChild1.StyleSetFlexShrink(1)
+	rootChild1.StyleSetFlexBasisPercent(0)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetFlexGrow(1)
+	rootChild2.StyleSetFlexShrink(1)
+	rootChild2.StyleSetFlexBasisPercent(0)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 80, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 30, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild2.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 80, root.LayoutGetWidth())
+	assertFloatEqual(t, 100, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 30, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild2.LayoutGetHeight())
+}
+
+func TestColumn_gap_inflexible(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(80)
+	root.StyleSetHeight(100)
+	root.StyleSetGap(GutterColumn, 10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(20)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(20)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+
+	assertFloatEqual(t, 30, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetWidth())
+
+	assertFloatEqual(t, 60, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 20, rootChild2.LayoutGetWidth())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 60, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetLeft())
+}
+
+func TestColumn_gap_justify_center(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetJustifyContent(JustifyCenter)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	root.StyleSetGap(GutterColumn, 10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(20)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(20)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 10, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 70, rootChild2.LayoutGetLeft())
+}
+
+func TestColumn_gap_justify_space_between(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetJustifyContent(JustifySpaceBetween)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	root.StyleSetGap(GutterColumn, 10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(20)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetWidth(20)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 80, rootChild2.LayoutGetLeft())
+}
+
+func TestColumn_gap_percent(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(100)
+	root.StyleSetGapPercent(GutterColumn, 10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(20)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetLeft())
+}
+
+func TestColumn_gap_overrides_gap_all(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	root.StyleSetGap(GutterAll, 10)
+	root.StyleSetGap(GutterColumn, 5)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(20)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 25, rootChild1.LayoutGetLeft())
+}
+
+func TestRow_gap_column(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetGap(GutterRow, 10)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetHeight(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetHeight(20)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+	assertFloatEqual(t, 50, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 30, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetHeight())
+}
+
+func TestColumn_row_gap_wrapping(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetWidth(80)
+	root.StyleSetGap(GutterColumn, 10)
+	root.StyleSetGap(GutterRow, 20)
+
+	children := make([]*Node, 6)
+	for i := range children {
+		child := NewNodeWithConfig(config)
+		child.StyleSetWidth(20)
+		child.StyleSetHeight(20)
+		root.InsertChild(child, i)
+		children[i] = child
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 80, root.LayoutGetWidth())
+	assertFloatEqual(t, 60, root.LayoutGetHeight())
+
+	expLeft := []float32{0, 30, 60, 0, 30, 60}
+	expTop := []float32{0, 0, 0, 40, 40, 40}
+	for i, child := range children {
+		assertFloatEqual(t, expLeft[i], child.LayoutGetLeft())
+		assertFloatEqual(t, expTop[i], child.LayoutGetTop())
+		assertFloatEqual(t, 20, child.LayoutGetWidth())
+		assertFloatEqual(t, 20, child.LayoutGetHeight())
+	}
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	expLeft = []float32{60, 30, 0, 60, 30, 0}
+	for i, child := range children {
+		assertFloatEqual(t, expLeft[i], child.LayoutGetLeft())
+		assertFloatEqual(t, expTop[i], child.LayoutGetTop())
+	}
+}
+
+func TestRow_gap_align_content_space_between(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetAlignContent(AlignSpaceBetween)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(120)
+	root.StyleSetGap(GutterRow, 10)
+
+	children := make([]*Node, 4)
+	for i := range children {
+		child := NewNodeWithConfig(config)
+		child.StyleSetWidth(40)
+		child.StyleSetHeight(20)
+		root.InsertChild(child, i)
+		children[i] = child
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	expLeft := []float32{0, 40, 0, 40}
+	expTop := []float32{0, 0, 100, 100}
+	for i, child := range children {
+		assertFloatEqual(t, expLeft[i], child.LayoutGetLeft())
+		assertFloatEqual(t, expTop[i], child.LayoutGetTop())
+	}
+}
+
+func TestGap_print(t *testing.T) {
+	root := NewNode()
+	root.StyleSetGap(GutterColumn, 4)
+	root.StyleSetGapPercent(GutterRow, 50)
+
+	w := &bytes.Buffer{}
+	printer := NewNodePrinter(w, PrintOptionsStyle)
+	printer.Print(root)
+	assert.Equal(t, `<div style="column-gap: 4px; row-gap: 50%; "></div>`, w.String())
+}
@@ -0,0 +1,789 @@
+package flex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GridTrack is the size of a row or a column in grid-template-rows and
+// grid-template-columns. Min and Max are sizing functions of
+// minmax(Min, Max): points, percent or auto. A track with Fr > 0 is flexible,
+// Max is ignored and the track gets Fr share of the free space
+type GridTrack struct {
+	Min Value
+	Max Value
+	Fr  float32
+}
+
+// GridTrackPoints returns a track of fixed size, like "100px"
+func GridTrackPoints(points float32) GridTrack {
+	v := Value{Value: points, Unit: UnitPoint}
+	return GridTrack{Min: v, Max: v}
+}
+
+// GridTrackPercent returns a track sized in percent of the grid container,
+// like "50%"
+func GridTrackPercent(percent float32) GridTrack {
+	v := Value{Value: percent, Unit: UnitPercent}
+	return GridTrack{Min: v, Max: v}
+}
+
+// GridTrackAuto returns a track sized to fit its items, like "auto"
+func GridTrackAuto() GridTrack {
+	return GridTrack{Min: autoValue, Max: autoValue}
+}
+
+// GridTrackFr returns a flexible track, like "1fr"
+func GridTrackFr(fr float32) GridTrack {
+	return GridTrack{Min: autoValue, Max: autoValue, Fr: fr}
+}
+
+// GridTrackMinMax returns a track like "minmax(100px, 1fr)". min is the
+// Min of min track, max is the Max (or Fr) of max track
+func GridTrackMinMax(min GridTrack, max GridTrack) GridTrack {
+	return GridTrack{Min: min.Min, Max: max.Max, Fr: max.Fr}
+}
+
+// GridRepeat returns tracks repeated count times, like "repeat(3, 1fr)"
+func GridRepeat(count int, tracks ...GridTrack) []GridTrack {
+	var res []GridTrack
+	for i := 0; i < count; i++ {
+		res = append(res, tracks...)
+	}
+	return res
+}
+
+// GridPlacement is the placement of a grid item in columns (grid-column)
+// or rows (grid-row). Start and End are 1-based grid lines, negative lines
+// count from the end of the explicit grid and 0 is auto. Span is the number of
+// tracks the item spans when Start or End is auto, 1 if 0.
+// The zero value is auto placement
+type GridPlacement struct {
+	Start int
+	End   int
+	Span  int
+}
+
+func gridTrackEq(a GridTrack, b GridTrack) bool {
+	return valueEq(a.Min, b.Min) && valueEq(a.Max, b.Max) && feq(a.Fr, b.Fr)
+}
+
+func gridTracksEq(a []GridTrack, b []GridTrack) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !gridTrackEq(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// gridTrackToString returns track in CSS syntax
+func gridTrackToString(track GridTrack) string {
+	if track.Fr > 0 {
+		if track.Min.Unit == UnitAuto {
+			return fmt.Sprintf("%gfr", track.Fr)
+		}
+		return fmt.Sprintf("minmax(%s, %gfr)", valueToString(track.Min), track.Fr)
+	}
+	if valueEq(track.Min, track.Max) {
+		return valueToString(track.Min)
+	}
+	return fmt.Sprintf("minmax(%s, %s)", valueToString(track.Min), valueToString(track.Max))
+}
+
+// gridTracksToString returns value of grid-template-columns or
+// grid-template-rows
+func gridTracksToString(tracks []GridTrack) string {
+	if len(tracks) == 0 {
+		return "none"
+	}
+	var parts []string
+	for _, track := range tracks {
+		parts = append(parts, gridTrackToString(track))
+	}
+	return strings.Join(parts, " ")
+}
+
+// gridPlacementToString returns value of grid-column or grid-row
+func gridPlacementToString(placement GridPlacement) string {
+	span := ""
+	if placement.Span > 1 {
+		span = fmt.Sprintf("span %d", placement.Span)
+	}
+	switch {
+	case placement.Start != 0 && placement.End != 0:
+		return fmt.Sprintf("%d / %d", placement.Start, placement.End)
+	case placement.Start != 0 && span != "":
+		return fmt.Sprintf("%d / %s", placement.Start, span)
+	case placement.Start != 0:
+		return fmt.Sprintf("%d", placement.Start)
+	case placement.End != 0 && span != "":
+		return fmt.Sprintf("%s / %d", span, placement.End)
+	case placement.End != 0:
+		return fmt.Sprintf("auto / %d", placement.End)
+	case span != "":
+		return span
+	}
+	return "auto"
+}
+
+const (
+	gridAxisColumn = iota
+	gridAxisRow
+)
+
+// gridItem is an in-flow child of a grid container. start and span are
+// indexed by gridAxisColumn and gridAxisRow, start is -1 until the item
+// is placed
+type gridItem struct {
+	node  *Node
+	start [2]int
+	span  [2]int
+}
+
+// gridMaxTracks limits the number of tracks an item can be placed in, like
+// browsers do, so that huge lines or spans don't allocate huge grids
+const gridMaxTracks = 1000
+
+// gridResolvePlacement returns the first track and the number of tracks of
+// placement in a grid with explicitCount tracks. start is -1 for auto
+// placement. Tracks are clamped to the first gridMaxTracks
+func gridResolvePlacement(placement GridPlacement, explicitCount int) (start int, span int) {
+	line := func(l int) int {
+		l = maxInt(-gridMaxTracks-1, minInt(l, gridMaxTracks+1))
+		if l > 0 {
+			return l - 1
+		}
+		return explicitCount + 1 + l
+	}
+	span = minInt(placement.Span, gridMaxTracks)
+	if span < 1 {
+		span = 1
+	}
+	switch {
+	case placement.Start != 0 && placement.End != 0:
+		s, e := line(placement.Start), line(placement.End)
+		if s > e {
+			s, e = e, s
+		}
+		if s == e {
+			e = s + 1
+		}
+		start, span = s, e-s
+	case placement.Start != 0:
+		start = line(placement.Start)
+	case placement.End != 0:
+		start = line(placement.End) - span
+	default:
+		return -1, span
+	}
+	// implicit tracks before the explicit grid are not supported
+	if start < 0 {
+		start = 0
+	}
+	start = minInt(start, gridMaxTracks-1)
+	span = minInt(span, gridMaxTracks-start)
+	return start, span
+}
+
+// gridOccupancy tracks cells of the grid taken by placed items
+type gridOccupancy struct {
+	rows [][]bool
+}
+
+func (grid *gridOccupancy) fits(row int, column int, rowSpan int, columnSpan int) bool {
+	for r := row; r < row+rowSpan && r < len(grid.rows); r++ {
+		for c := column; c < column+columnSpan && c < len(grid.rows[r]); c++ {
+			if grid.rows[r][c] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (grid *gridOccupancy) occupy(item *gridItem) {
+	row, column := item.start[gridAxisRow], item.start[gridAxisColumn]
+	for r := row; r < row+item.span[gridAxisRow]; r++ {
+		for r >= len(grid.rows) {
+			grid.rows = append(grid.rows, nil)
+		}
+		for c := column; c < column+item.span[gridAxisColumn]; c++ {
+			for c >= len(grid.rows[r]) {
+				grid.rows[r] = append(grid.rows[r], false)
+			}
+			grid.rows[r][c] = true
+		}
+	}
+}
+
+// gridPlaceItems places items in the grid with the row auto-placement
+// algorithm and returns the number of columns and rows, including implicit
+// tracks
+func gridPlaceItems(node *Node, items []*gridItem) (columnCount int, rowCount int) {
+	explicitColumns := len(node.Style.GridTemplateColumns)
+	explicitRows := len(node.Style.GridTemplateRows)
+
+	columnCount = explicitColumns
+	if columnCount == 0 {
+		columnCount = 1
+	}
+	for _, item := range items {
+		item.start[gridAxisColumn], item.span[gridAxisColumn] = gridResolvePlacement(item.node.Style.GridColumn, explicitColumns)
+		item.start[gridAxisRow], item.span[gridAxisRow] = gridResolvePlacement(item.node.Style.GridRow, explicitRows)
+		columnCount = maxInt(columnCount, maxInt(item.start[gridAxisColumn], 0)+item.span[gridAxisColumn])
+	}
+
+	grid := &gridOccupancy{}
+
+	// Items with definite position
+	for _, item := range items {
+		if item.start[gridAxisColumn] >= 0 && item.start[gridAxisRow] >= 0 {
+			grid.occupy(item)
+		}
+	}
+
+	// Items locked to a row
+	for _, item := range items {
+		if item.start[gridAxisRow] < 0 || item.start[gridAxisColumn] >= 0 {
+			continue
+		}
+		column := 0
+		for !grid.fits(item.start[gridAxisRow], column, item.span[gridAxisRow], item.span[gridAxisColumn]) {
+			column++
+		}
+		item.start[gridAxisColumn] = column
+		columnCount = maxInt(columnCount, column+item.span[gridAxisColumn])
+		grid.occupy(item)
+	}
+
+	// Remaining items, in order
+	cursorRow, cursorColumn := 0, 0
+	for _, item := range items {
+		if item.start[gridAxisRow] >= 0 {
+			continue
+		}
+		rowSpan, columnSpan := item.span[gridAxisRow], item.span[gridAxisColumn]
+		if column := item.start[gridAxisColumn]; column >= 0 {
+			if column < cursorColumn {
+				cursorRow++
+			}
+			for !grid.fits(cursorRow, column, rowSpan, columnSpan) {
+				cursorRow++
+			}
+			cursorColumn = column
+		} else {
+			for {
+				if cursorColumn+columnSpan > columnCount {
+					cursorRow++
+					cursorColumn = 0
+					continue
+				}
+				if grid.fits(cursorRow, cursorColumn, rowSpan, columnSpan) {
+					break
+				}
+				cursorColumn++
+			}
+			item.start[gridAxisColumn] = cursorColumn
+			cursorColumn += columnSpan
+		}
+		item.start[gridAxisRow] = cursorRow
+		grid.occupy(item)
+	}
+
+	rowCount = explicitRows
+	for _, item := range items {
+		rowCount = maxInt(rowCount, item.start[gridAxisRow]+item.span[gridAxisRow])
+	}
+	return columnCount, rowCount
+}
+
+// gridTrack is a row or a column during layout
+type gridTrack struct {
+	// base is the size of the track before free space is distributed
+	base float32
+	// limit is the growth limit of the track, Undefined for flexible tracks
+	limit float32
+	fr    float32
+	// minContent and maxContent are set for tracks with auto Min and
+	// auto Max that are sized by their items
+	minContent bool
+	maxContent bool
+
+	size   float32
+	offset float32
+}
+
+// gridNewTracks returns count tracks, sized by templates or auto for
+// implicit tracks. Percentages of undefined innerSize are treated as auto
+func gridNewTracks(templates []GridTrack, count int, innerSize float32) []gridTrack {
+	tracks := make([]gridTrack, count)
+	for i := range tracks {
+		template := GridTrackAuto()
+		if i < len(templates) {
+			template = templates[i]
+		}
+		track := &tracks[i]
+		min := resolveValue(&template.Min, innerSize)
+		if FloatIsUndefined(min) {
+			track.minContent = true
+			min = 0
+		}
+		track.base = fmaxf(min, 0)
+		track.limit = Undefined
+		if template.Fr > 0 {
+			track.fr = template.Fr
+			continue
+		}
+		track.limit = resolveValue(&template.Max, innerSize)
+		if FloatIsUndefined(track.limit) {
+			track.maxContent = true
+		} else if track.limit < track.base {
+			track.limit = track.base
+		}
+	}
+	return tracks
+}
+
+// gridTracksSize returns the size of tracks and gaps between them
+func gridTracksSize(tracks []gridTrack, gap float32) float32 {
+	var size float32
+	for i := range tracks {
+		size += tracks[i].size
+	}
+	if len(tracks) > 1 {
+		size += gap * float32(len(tracks)-1)
+	}
+	return size
+}
+
+// gridAreaSize returns the size of span tracks starting at start
+func gridAreaSize(tracks []gridTrack, start int, span int, gap float32) float32 {
+	return gridTracksSize(tracks[start:start+span], gap)
+}
+
+// gridResolveIntrinsicSizes sizes tracks with auto Min and auto Max by
+// contributions of items. contribution returns the outer min-content or
+// max-content size of item
+func gridResolveIntrinsicSizes(tracks []gridTrack, items []*gridItem, axis int, gap float32, contribution func(item *gridItem, minContent bool) float32) {
+	for _, item := range items {
+		if item.span[axis] != 1 {
+			continue
+		}
+		track := &tracks[item.start[axis]]
+		if track.minContent {
+			track.base = fmaxf(track.base, contribution(item, true))
+		}
+		if track.maxContent {
+			track.limit = fmaxf(track.limit, contribution(item, false))
+		}
+	}
+
+	// Items spanning several tracks distribute the size that doesn't fit
+	// equally to the spanned intrinsic tracks. Items spanning flexible tracks
+	// are sized by the flexible tracks
+	for _, item := range items {
+		span := item.span[axis]
+		if span == 1 {
+			continue
+		}
+		spanned := tracks[item.start[axis] : item.start[axis]+span]
+		var flexible bool
+		var minContentCount, maxContentCount int
+		base, limit := gap*float32(span-1), gap*float32(span-1)
+		for i := range spanned {
+			flexible = flexible || spanned[i].fr > 0
+			base += spanned[i].base
+			limit += fmaxf(spanned[i].limit, spanned[i].base)
+			if spanned[i].minContent {
+				minContentCount++
+			}
+			if spanned[i].maxContent {
+				maxContentCount++
+			}
+		}
+		if flexible {
+			continue
+		}
+		if extra := contribution(item, true) - base; extra > 0 && minContentCount > 0 {
+			for i := range spanned {
+				if spanned[i].minContent {
+					spanned[i].base += extra / float32(minContentCount)
+				}
+			}
+		}
+		if extra := contribution(item, false) - limit; extra > 0 && maxContentCount > 0 {
+			for i := range spanned {
+				if spanned[i].maxContent {
+					spanned[i].limit = fmaxf(spanned[i].limit, spanned[i].base) + extra/float32(maxContentCount)
+				}
+			}
+		}
+	}
+
+	for i := range tracks {
+		track := &tracks[i]
+		if track.fr == 0 && (FloatIsUndefined(track.limit) || track.limit < track.base) {
+			track.limit = track.base
+		}
+	}
+}
+
+// gridSizeTracks sets size of tracks from their base sizes and growth
+// limits. Free space of innerSize is distributed to non-flexible tracks up to
+// their growth limits, and what remains to flexible tracks. Undefined
+// innerSize sizes tracks to fit their contents
+func gridSizeTracks(tracks []gridTrack, items []*gridItem, axis int, innerSize float32, gap float32, contribution func(item *gridItem, minContent bool) float32) {
+	hasFlexible := false
+	for i := range tracks {
+		tracks[i].size = tracks[i].base
+		if FloatIsUndefined(innerSize) && tracks[i].fr == 0 {
+			tracks[i].size = tracks[i].limit
+		}
+		hasFlexible = hasFlexible || tracks[i].fr > 0
+	}
+
+	if !FloatIsUndefined(innerSize) {
+		free := innerSize - gridTracksSize(tracks, gap)
+		for free > 0.0001 {
+			growable := 0
+			for i := range tracks {
+				if tracks[i].fr == 0 && tracks[i].limit > tracks[i].size {
+					growable++
+				}
+			}
+			if growable == 0 {
+				break
+			}
+			share := free / float32(growable)
+			for i := range tracks {
+				track := &tracks[i]
+				if track.fr == 0 && track.limit > track.size {
+					grow := fminf(share, track.limit-track.size)
+					track.size += grow
+					free -= grow
+				}
+			}
+		}
+	}
+
+	if !hasFlexible {
+		return
+	}
+
+	var frSize float32
+	if !FloatIsUndefined(innerSize) {
+		frSize = gridFindFrSize(tracks, innerSize-gridGapsSize(tracks, gap))
+	} else {
+		for i := range tracks {
+			if tracks[i].fr > 0 {
+				frSize = fmaxf(frSize, tracks[i].base/fmaxf(tracks[i].fr, 1))
+			}
+		}
+		for _, item := range items {
+			track := &tracks[item.start[axis]]
+			if item.span[axis] == 1 && track.fr > 0 {
+				frSize = fmaxf(frSize, contribution(item, false)/fmaxf(track.fr, 1))
+			}
+		}
+	}
+	for i := range tracks {
+		if tracks[i].fr > 0 {
+			tracks[i].size = fmaxf(tracks[i].base, frSize*tracks[i].fr)
+		}
+	}
+}
+
+func gridGapsSize(tracks []gridTrack, gap float32) float32 {
+	if len(tracks) < 2 {
+		return 0
+	}
+	return gap * float32(len(tracks)-1)
+}
+
+// gridFindFrSize returns the size of 1fr that fills space. Flexible tracks
+// whose base size is larger than their share are treated as inflexible
+func gridFindFrSize(tracks []gridTrack, space float32) float32 {
+	inflexible := make([]bool, len(tracks))
+	for {
+		leftover := space
+		var flexFactors float32
+		for i := range tracks {
+			if tracks[i].fr > 0 && !inflexible[i] {
+				flexFactors += tracks[i].fr
+			} else if tracks[i].fr > 0 {
+				leftover -= tracks[i].base
+			} else {
+				leftover -= tracks[i].size
+			}
+		}
+		flexFactors = fmaxf(flexFactors, 1)
+		frSize := fmaxf(leftover, 0) / flexFactors
+		changed := false
+		for i := range tracks {
+			if tracks[i].fr > 0 && !inflexible[i] && frSize*tracks[i].fr < tracks[i].base {
+				inflexible[i] = true
+				changed = true
+			}
+		}
+		if !changed {
+			return frSize
+		}
+	}
+}
+
+// gridAlignTracks sets offsets of tracks, distributing free space of
+// innerSize like justify-content
+func gridAlignTracks(tracks []gridTrack, innerSize float32, gap float32, justify Justify) {
+	free := innerSize - gridTracksSize(tracks, gap)
+	if FloatIsUndefined(free) {
+		free = 0
+	}
+	count := float32(len(tracks))
+	leading, between := float32(0), gap
+	switch justify {
+	case JustifyCenter:
+		leading = free / 2
+	case JustifyFlexEnd:
+		leading = free
+	case JustifySpaceBetween:
+		if free > 0 && count > 1 {
+			between += free / (count - 1)
+		}
+	case JustifySpaceAround:
+		if free > 0 {
+			leading = free / count / 2
+			between += free / count
+		} else {
+			leading = free / 2
+		}
+	case JustifySpaceEvenly:
+		if free > 0 {
+			leading = free / (count + 1)
+			between += free / (count + 1)
+		} else {
+			leading = free / 2
+		}
+	}
+	offset := leading
+	for i := range tracks {
+		tracks[i].offset = offset
+		offset += tracks[i].size + between
+	}
+}
+
+// gridAlignRows sets offsets of rows like align-content. Stretch grows
+// auto rows equally
+func gridAlignRows(tracks []gridTrack, innerSize float32, gap float32, align Align) {
+	justify := JustifyFlexStart
+	switch align {
+	case AlignCenter:
+		justify = JustifyCenter
+	case AlignFlexEnd:
+		justify = JustifyFlexEnd
+	case AlignSpaceBetween:
+		justify = JustifySpaceBetween
+	case AlignSpaceAround:
+		justify = JustifySpaceAround
+	case AlignSpaceEvenly:
+		justify = JustifySpaceEvenly
+	case AlignStretch:
+		free := innerSize - gridTracksSize(tracks, gap)
+		autoCount := 0
+		for i := range tracks {
+			if tracks[i].maxContent {
+				autoCount++
+			}
+		}
+		if free > 0 && autoCount > 0 {
+			for i := range tracks {
+				if tracks[i].maxContent {
+					tracks[i].size += free / float32(autoCount)
+				}
+			}
+		}
+	}
+	gridAlignTracks(tracks, innerSize, gap, justify)
+}
+
+// nodeGridLayoutImpl lays out children of node with display: grid. It is
+// called by nodelayoutImpl, which has already set margin, border and padding
+// of node
+func nodeGridLayoutImpl(node *Node, children []*Node, availableWidth float32, availableHeight float32,
+	direction Direction, widthMeasureMode MeasureMode, heightMeasureMode MeasureMode,
+	parentWidth float32, parentHeight float32, performLayout bool, config *Config, ctx *layoutContext) {
+	node.Layout.HadOverflow = false
+
+	rowAxis := resolveFlexDirection(FlexDirectionRow, direction)
+	paddingAndBorderAxisRow := nodePaddingAndBorderForAxis(node, FlexDirectionRow, parentWidth)
+	paddingAndBorderAxisColumn := nodePaddingAndBorderForAxis(node, FlexDirectionColumn, parentWidth)
+	marginAxisRow := nodeMarginForAxis(node, FlexDirectionRow, parentWidth)
+	marginAxisColumn := nodeMarginForAxis(node, FlexDirectionColumn, parentWidth)
+
+	// Inner size is definite only for exact measure mode, like in
+	// nodelayoutImpl it's bound by min and max size
+	definiteInnerWidth := Undefined
+	if widthMeasureMode == MeasureModeExactly {
+		definiteInnerWidth = nodeBoundAxis(node, FlexDirectionRow, availableWidth-marginAxisRow, parentWidth, parentWidth) -
+			paddingAndBorderAxisRow
+	}
+	definiteInnerHeight := Undefined
+	if heightMeasureMode == MeasureModeExactly {
+		definiteInnerHeight = nodeBoundAxis(node, FlexDirectionColumn, availableHeight-marginAxisColumn, parentHeight, parentWidth) -
+			paddingAndBorderAxisColumn
+	}
+
+	var items []*gridItem
+	var absoluteChildren []*Node
+	for _, child := range children {
+		if child.Style.Display == DisplayNone {
+			zeroOutLayoutRecursivly(child)
+			nodeSetHasNewLayout(child)
+			child.IsDirty = false
+			continue
+		}
+		resolveDimensions(child)
+		if child.Style.PositionType == PositionTypeAbsolute {
+			absoluteChildren = append(absoluteChildren, child)
+			continue
+		}
+		items = append(items, &gridItem{node: child})
+	}
+	columnCount, rowCount := gridPlaceItems(node, items)
+
+	// STEP 1: SIZING COLUMNS
+	columnGap := nodeGapForAxis(node, FlexDirectionRow, definiteInnerWidth)
+	columns := gridNewTracks(node.Style.GridTemplateColumns, columnCount, definiteInnerWidth)
+	columnContribution := func(item *gridItem, minContent bool) float32 {
+		return nodeMeasureChild(item.node, FlexDirectionRow, Undefined, minContent,
+			definiteInnerWidth, definiteInnerHeight, direction, "grid-measure", config, ctx)
+	}
+	gridResolveIntrinsicSizes(columns, items, gridAxisColumn, columnGap, columnContribution)
+	gridSizeTracks(columns, items, gridAxisColumn, definiteInnerWidth, columnGap, columnContribution)
+
+	width := nodeSizeForContent(node, FlexDirectionRow, gridTracksSize(columns, columnGap),
+		availableWidth-marginAxisRow, widthMeasureMode, parentWidth, parentWidth)
+	innerWidth := width - paddingAndBorderAxisRow
+	if widthMeasureMode != MeasureModeExactly && !FloatsEqual(innerWidth, gridTracksSize(columns, columnGap)) {
+		// min or max size of the container changed the space for columns
+		gridSizeTracks(columns, items, gridAxisColumn, innerWidth, columnGap, columnContribution)
+	}
+
+	// STEP 2: SIZING ROWS
+	rowGap := nodeGapForAxis(node, FlexDirectionColumn, definiteInnerHeight)
+	rows := gridNewTracks(node.Style.GridTemplateRows, rowCount, definiteInnerHeight)
+	rowContribution := func(item *gridItem, minContent bool) float32 {
+		areaWidth := gridAreaSize(columns, item.start[gridAxisColumn], item.span[gridAxisColumn], columnGap)
+		return nodeMeasureChild(item.node, FlexDirectionColumn, areaWidth, false,
+			innerWidth, definiteInnerHeight, direction, "grid-measure", config, ctx)
+	}
+	gridResolveIntrinsicSizes(rows, items, gridAxisRow, rowGap, rowContribution)
+	gridSizeTracks(rows, items, gridAxisRow, definiteInnerHeight, rowGap, rowContribution)
+
+	height := nodeSizeForContent(node, FlexDirectionColumn, gridTracksSize(rows, rowGap),
+		availableHeight-marginAxisColumn, heightMeasureMode, parentHeight, parentWidth)
+	innerHeight := height - paddingAndBorderAxisColumn
+	if heightMeasureMode != MeasureModeExactly && !FloatsEqual(innerHeight, gridTracksSize(rows, rowGap)) {
+		gridSizeTracks(rows, items, gridAxisRow, innerHeight, rowGap, rowContribution)
+	}
+
+	node.Layout.measuredDimensions[DimensionWidth] = width
+	node.Layout.measuredDimensions[DimensionHeight] = height
+
+	if !performLayout {
+		return
+	}
+
+	// STEP 3: POSITIONING ITEMS IN THEIR GRID AREAS
+	gridAlignTracks(columns, innerWidth, columnGap, node.Style.JustifyContent)
+	gridAlignRows(rows, innerHeight, rowGap, node.Style.AlignContent)
+
+	leadingPaddingAndBorderRow := nodeLeadingPaddingAndBorder(node, rowAxis, parentWidth)
+	leadingPaddingAndBorderColumn := nodeLeadingPaddingAndBorder(node, FlexDirectionColumn, parentWidth)
+	for _, item := range items {
+		child := item.node
+		column, row := &columns[item.start[gridAxisColumn]], &rows[item.start[gridAxisRow]]
+		areaWidth := gridAreaSize(columns, item.start[gridAxisColumn], item.span[gridAxisColumn], columnGap)
+		areaHeight := gridAreaSize(rows, item.start[gridAxisRow], item.span[gridAxisRow], rowGap)
+		marginRow := nodeMarginForAxis(child, FlexDirectionRow, innerWidth)
+		marginColumn := nodeMarginForAxis(child, FlexDirectionColumn, innerWidth)
+		align := nodeAlignItem(node, child)
+
+		childWidth, childWidthMeasureMode := areaWidth, MeasureModeExactly
+		if nodeIsStyleDimDefined(child, FlexDirectionRow, areaWidth) {
+			childWidth = resolveValue(child.resolvedDimensions[DimensionWidth], areaWidth) + marginRow
+		}
+		childHeight, childHeightMeasureMode := Undefined, MeasureModeUndefined
+		if nodeIsStyleDimDefined(child, FlexDirectionColumn, areaHeight) {
+			childHeight = resolveValue(child.resolvedDimensions[DimensionHeight], areaHeight) + marginColumn
+			childHeightMeasureMode = MeasureModeExactly
+		} else if align == AlignStretch {
+			childHeight = areaHeight
+			childHeightMeasureMode = MeasureModeExactly
+		} else {
+			constrainMaxSizeForMode(child, FlexDirectionColumn, areaHeight, innerWidth, &childHeightMeasureMode, &childHeight)
+		}
+
+		layoutNodeInternal(child,
+			childWidth,
+			childHeight,
+			direction,
+			childWidthMeasureMode,
+			childHeightMeasureMode,
+			innerWidth,
+			innerHeight,
+			true,
+			"grid",
+			config,
+			ctx)
+		child.lineIndex = item.start[gridAxisRow]
+
+		child.Layout.Position[pos[rowAxis]] = leadingPaddingAndBorderRow + column.offset +
+			nodeLeadingMargin(child, rowAxis, innerWidth) +
+			nodeRelativePosition(child, rowAxis, innerWidth)
+
+		top := leadingPaddingAndBorderColumn + row.offset +
+			nodeLeadingMargin(child, FlexDirectionColumn, innerWidth) +
+			nodeRelativePosition(child, FlexDirectionColumn, innerHeight)
+		free := areaHeight - child.Layout.measuredDimensions[DimensionHeight] - marginColumn
+		switch align {
+		case AlignCenter:
+			top += free / 2
+		case AlignFlexEnd:
+			top += free
+		}
+		child.Layout.Position[EdgeTop] = top
+
+		if rowAxis == FlexDirectionRowReverse {
+			nodeSetChildTrailingPosition(node, child, rowAxis)
+		}
+	}
+
+	// STEP 4: SIZING AND POSITIONING ABSOLUTE CHILDREN
+	if nodeIsContainingBlock(node) {
+		// Without insets absolute children are at the start of the content
+		// box, like absolute children of a static parent
+		for _, child := range absoluteChildren {
+			nodeAbsoluteSizeChild(node, child, innerWidth, MeasureModeExactly, innerHeight, direction, config, ctx)
+			nodeAbsolutePositionDescendant(node, node, child, 0, 0, innerWidth, innerHeight, direction)
+		}
+		for _, item := range items {
+			if item.node.Style.PositionType != PositionTypeStatic {
+				continue
+			}
+			nodeAbsoluteLayoutDescendants(node,
+				item.node,
+				item.node.Layout.Position[EdgeLeft],
+				item.node.Layout.Position[EdgeTop],
+				innerWidth,
+				MeasureModeExactly,
+				innerHeight,
+				direction,
+				config,
+				ctx)
+		}
+	}
+}
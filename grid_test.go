@@ -0,0 +1,473 @@
+package flex
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrid_fixed_and_fr_tracks(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayGrid)
+	root.StyleSetGridTemplateColumns(GridTrackPoints(120), GridTrackFr(1), GridTrackFr(2))
+	root.StyleSetGridTemplateRows(GridTrackPoints(50), GridTrackFr(1))
+	root.StyleSetWidth(300)
+	root.StyleSetHeight(200)
+
+	var children []*Node
+	for i := 0; i < 6; i++ {
+		child := NewNodeWithConfig(config)
+		root.InsertChild(child, i)
+		children = append(children, child)
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 300, root.LayoutGetWidth())
+	assertFloatEqual(t, 200, root.LayoutGetHeight())
+
+	expected := [][4]float32{
+		{0, 0, 120, 50},
+		{120, 0, 60, 50},
+		{180, 0, 120, 50},
+		{0, 50, 120, 150},
+		{120, 50, 60, 150},
+		{180, 50, 120, 150},
+	}
+	for i, child := range children {
+		assertFloatEqual(t, expected[i][0], child.LayoutGetLeft())
+		assertFloatEqual(t, expected[i][1], child.LayoutGetTop())
+		assertFloatEqual(t, expected[i][2], child.LayoutGetWidth())
+		assertFloatEqual(t, expected[i][3], child.LayoutGetHeight())
+	}
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	for i, child := range children {
+		assertFloatEqual(t, 300-expected[i][0]-expected[i][2], child.LayoutGetLeft())
+		assertFloatEqual(t, expected[i][1], child.LayoutGetTop())
+		assertFloatEqual(t, expected[i][2], child.LayoutGetWidth())
+		assertFloatEqual(t, expected[i][3], child.LayoutGetHeight())
+	}
+}
+
+func TestGrid_placement_and_gap(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayGrid)
+	root.StyleSetGridTemplateColumns(GridRepeat(4, GridTrackFr(1))...)
+	root.StyleSetGap(GutterAll, 10)
+	root.StyleSetWidth(230)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetGridColumn(GridPlacement{Start: 1, Span: 2})
+	rootChild0.StyleSetHeight(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetGridColumn(GridPlacement{Start: 4})
+	rootChild1.StyleSetGridRow(GridPlacement{Start: 1})
+	rootChild1.StyleSetHeight(30)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetHeight(10)
+	root.InsertChild(rootChild2, 2)
+
+	rootChild3 := NewNodeWithConfig(config)
+	rootChild3.StyleSetGridColumn(GridPlacement{Span: 3})
+	rootChild3.StyleSetHeight(10)
+	root.InsertChild(rootChild3, 3)
+
+	rootChild4 := NewNodeWithConfig(config)
+	rootChild4.StyleSetGridRow(GridPlacement{Start: 2})
+	root.InsertChild(rootChild4, 4)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 230, root.LayoutGetWidth())
+	assertFloatEqual(t, 50, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 110, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 180, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 120, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild3.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild3.LayoutGetTop())
+	assertFloatEqual(t, 170, rootChild3.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild3.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild4.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild4.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild4.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild4.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 0, root.LayoutGetLeft())
+	assertFloatEqual(t, 0, root.LayoutGetTop())
+	assertFloatEqual(t, 230, root.LayoutGetWidth())
+	assertFloatEqual(t, 50, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 120, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 110, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild3.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild3.LayoutGetTop())
+	assertFloatEqual(t, 170, rootChild3.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild3.LayoutGetHeight())
+
+	assertFloatEqual(t, 180, rootChild4.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild4.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild4.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild4.LayoutGetHeight())
+}
+
+func TestGrid_auto_track_measure_func(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayGrid)
+	root.StyleSetGridTemplateColumns(GridTrackAuto(), GridTrackFr(1))
+	root.StyleSetWidth(200)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.SetMeasureFunc(func(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+		return Size{Width: 60, Height: 20}
+	})
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 200, root.LayoutGetWidth())
+	assertFloatEqual(t, 20, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 60, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 60, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 140, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetHeight())
+}
+
+func TestGrid_minmax_and_indefinite_size(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayGrid)
+	root.StyleSetGridTemplateColumns(GridTrackFr(1), GridTrackFr(1), GridTrackMinMax(GridTrackPoints(15), GridTrackPoints(40)))
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetWidth(30)
+	rootChild0.StyleSetHeight(10)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(50)
+	rootChild1.StyleSetHeight(10)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 140, root.LayoutGetWidth())
+	assertFloatEqual(t, 10, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 30, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
+
+	// max width makes space for fr tracks definite, they don't shrink below
+	// their contents
+	root.StyleSetMaxWidth(90)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 90, root.LayoutGetWidth())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 30, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 80, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 15, rootChild2.LayoutGetWidth())
+}
+
+func TestGrid_alignment(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayGrid)
+	root.StyleSetJustifyContent(JustifyCenter)
+	root.StyleSetGridTemplateColumns(GridTrackPoints(30), GridTrackPoints(30))
+	root.StyleSetGridTemplateRows(GridTrackPoints(100))
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetAlignSelf(AlignCenter)
+	rootChild0.StyleSetWidth(20)
+	rootChild0.StyleSetHeight(20)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetAlignSelf(AlignFlexEnd)
+	rootChild1.StyleSetHeight(30)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 20, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 50, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 70, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 30, rootChild1.LayoutGetHeight())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 60, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 70, rootChild1.LayoutGetTop())
+}
+
+func TestGrid_padding_margin_percent(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetDisplay(DisplayGrid)
+	root.StyleSetGridTemplateColumns(GridTrackPercent(50), GridTrackAuto())
+	root.StyleSetPadding(EdgeAll, 10)
+	root.StyleSetWidth(200)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetMargin(EdgeAll, 5)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetWidth(40)
+	rootChild1.StyleSetHeight(25)
+	root.InsertChild(rootChild1, 1)
+
+	rootChild2 := NewNodeWithConfig(config)
+	rootChild2.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild2.StyleSetPosition(EdgeLeft, 10)
+	rootChild2.StyleSetPosition(EdgeTop, 10)
+	rootChild2.StyleSetWidth(10)
+	rootChild2.StyleSetHeight(10)
+	root.InsertChild(rootChild2, 2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 200, root.LayoutGetWidth())
+	assertFloatEqual(t, 45, root.LayoutGetHeight())
+
+	assertFloatEqual(t, 15, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 15, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 80, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 15, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 100, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 10, rootChild1.LayoutGetTop())
+	assertFloatEqual(t, 40, rootChild1.LayoutGetWidth())
+	assertFloatEqual(t, 25, rootChild1.LayoutGetHeight())
+
+	assertFloatEqual(t, 10, rootChild2.LayoutGetLeft())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetWidth())
+	assertFloatEqual(t, 10, rootChild2.LayoutGetHeight())
+}
+
+func TestGrid_nested_in_flex(t *testing.T) {
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetDisplay(DisplayGrid)
+	rootChild0.StyleSetGridTemplateColumns(GridTrackPoints(20), GridTrackPoints(30))
+	rootChild0.StyleSetGridTemplateRows(GridTrackPoints(40))
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetGridColumn(GridPlacement{Start: -2})
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild1 := NewNodeWithConfig(config)
+	rootChild1.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 50, rootChild0.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild0.LayoutGetHeight())
+
+	assertFloatEqual(t, 20, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0Child0.LayoutGetTop())
+	assertFloatEqual(t, 30, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 40, rootChild0Child0.LayoutGetHeight())
+
+	assertFloatEqual(t, 50, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 50, rootChild1.LayoutGetWidth())
+}
+
+func TestGrid_style_setters(t *testing.T) {
+	root := NewNode()
+	root.StyleSetGridTemplateColumns(GridTrackFr(1))
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	root.StyleSetGridTemplateColumns(GridTrackFr(1))
+	assert.False(t, root.IsDirty)
+	root.StyleSetGridColumn(GridPlacement{})
+	assert.False(t, root.IsDirty)
+
+	root.StyleSetGridRow(GridPlacement{Start: 2})
+	assert.True(t, root.IsDirty)
+	assert.Equal(t, GridPlacement{Start: 2}, root.StyleGetGridRow())
+	assert.True(t, gridTracksEq([]GridTrack{GridTrackFr(1)}, root.StyleGetGridTemplateColumns()))
+}
+
+func TestGrid_parse_style(t *testing.T) {
+	style, err := ParseStyle(`
+		display: grid;
+		grid-template-columns: 100px repeat(2, minmax(50px, 1fr)) auto 25%;
+		grid-template-rows: repeat(2, 1.5fr 20px);
+		grid-row: 2 / span 3;
+		grid-column-start: span 2;
+		grid-column-end: -1;
+	`)
+	assert.NoError(t, err)
+	assert.Equal(t, DisplayGrid, style.Display)
+	minmax := GridTrackMinMax(GridTrackPoints(50), GridTrackFr(1))
+	assert.True(t, gridTracksEq([]GridTrack{GridTrackPoints(100), minmax, minmax, GridTrackAuto(), GridTrackPercent(25)}, style.GridTemplateColumns))
+	assert.True(t, gridTracksEq(GridRepeat(2, GridTrackFr(1.5), GridTrackPoints(20)), style.GridTemplateRows))
+	assert.Equal(t, GridPlacement{Start: 2, Span: 3}, style.GridRow)
+	assert.Equal(t, GridPlacement{End: -1, Span: 2}, style.GridColumn)
+
+	assert.Equal(t, "100px minmax(50px, 1fr) minmax(50px, 1fr) auto 25%", gridTracksToString(style.GridTemplateColumns))
+	assert.Equal(t, "2 / span 3", gridPlacementToString(style.GridRow))
+	assert.Equal(t, "span 2 / -1", gridPlacementToString(style.GridColumn))
+	assert.Equal(t, "auto", gridPlacementToString(GridPlacement{}))
+
+	style, err = ParseStyle("grid-column: 1/3; grid-row: span 2; grid-template-columns: none")
+	assert.NoError(t, err)
+	assert.Equal(t, GridPlacement{Start: 1, End: 3}, style.GridColumn)
+	assert.Equal(t, GridPlacement{Span: 2}, style.GridRow)
+	assert.Nil(t, style.GridTemplateColumns)
+
+	for _, css := range []string{
+		"grid-template-columns: minmax(1fr, 10px)",
+		"grid-template-columns: repeat(0, 1fr)",
+		"grid-template-columns: repeat(2, 1fr",
+		"grid-template-columns: 0fr",
+		"grid-template-rows: 10px)",
+		"grid-row: 0",
+		"grid-row: 1 / 2 / 3",
+		"grid-column: span 0",
+		"grid-column: span 20000000",
+		"grid-row: 1002",
+		"grid-row-end: -2147483648",
+		"grid-template-columns: repeat(1001, 10px)",
+		"grid-template-columns: 10px repeat(500, 1fr 10px)",
+	} {
+		_, err := ParseStyle(css)
+		assert.Error(t, err, css)
+	}
+}
+
+func TestGrid_huge_placement_is_clamped(t *testing.T) {
+	root := NewNode()
+	root.StyleSetDisplay(DisplayGrid)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetGridColumn(GridPlacement{Span: 20000000})
+	rootChild0.StyleSetGridRow(GridPlacement{Start: 3})
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNode()
+	rootChild1.StyleSetGridColumn(GridPlacement{Start: 1 << 30, End: -(1 << 30)})
+	rootChild1.StyleSetGridRow(GridPlacement{End: 1 << 30, Span: 1 << 30})
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	start, span := gridResolvePlacement(rootChild0.Style.GridColumn, 0)
+	assert.Equal(t, -1, start)
+	assert.Equal(t, gridMaxTracks, span)
+	start, span = gridResolvePlacement(rootChild0.Style.GridRow, 0)
+	assert.Equal(t, 2, start)
+	assert.Equal(t, 1, span)
+	start, span = gridResolvePlacement(rootChild1.Style.GridColumn, 0)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, gridMaxTracks, span)
+	start, span = gridResolvePlacement(rootChild1.Style.GridRow, 0)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, gridMaxTracks, span)
+	assertFloatEqual(t, 100, root.LayoutGetWidth())
+}
+
+func TestGrid_json_and_html_round_trip(t *testing.T) {
+	root := NewNode()
+	root.StyleSetDisplay(DisplayGrid)
+	root.StyleSetGridTemplateColumns(GridTrackPoints(10), GridTrackMinMax(GridTrackAuto(), GridTrackPoints(30)))
+	child := NewNode()
+	child.StyleSetGridColumn(GridPlacement{Start: 1, End: 3})
+	child.StyleSetGridRow(GridPlacement{Span: 2})
+	root.InsertChild(child, 0)
+
+	data, err := MarshalNodeJSON(root, JSONOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"style":{"display":"grid","grid-template-columns":"10px minmax(auto, 30px)"},"children":[{"style":{"grid-column":"1 / 3","grid-row":"span 2"}}]}`, string(data))
+	node, err := UnmarshalNodeJSON(data, JSONOptions{})
+	assert.NoError(t, err)
+	assert.True(t, styleEq(&root.Style, &node.Style))
+	assert.True(t, styleEq(&child.Style, &node.GetChild(0).Style))
+
+	var buf bytes.Buffer
+	NewNodePrinter(&buf, PrintOptionsStyle|PrintOptionsChildren).Print(root)
+	node, err = ParseHTMLNode(buf.String(), HTMLOptions{})
+	assert.NoError(t, err)
+	assert.True(t, styleEq(&root.Style, &node.Style))
+	assert.True(t, styleEq(&child.Style, &node.GetChild(0).Style))
+}
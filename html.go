@@ -0,0 +1,334 @@
+package flex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HTMLParseError describes an error in HTML passed to ParseHTML
+type HTMLParseError struct {
+	// Offset is byte offset of the error in the input
+	Offset int
+	// Line and Column are 1-based position of the error in the input
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *HTMLParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// HTMLOptions are options for ParseHTML
+type HTMLOptions struct {
+	// Config is used for parsed nodes. Default config if nil
+	Config *Config
+	// MeasureFunc is set on nodes printed by NodePrinter with
+	// has-custom-measure="true"
+	MeasureFunc MeasureFunc
+}
+
+// HTMLTree is a tree of nodes parsed by ParseHTML
+type HTMLTree struct {
+	// ID is id attribute of the root div. In Yoga gentest fixtures it's
+	// the name of the test
+	ID   string
+	Root *Node
+}
+
+// ParseHTML parses <div> trees printed by NodePrinter or from Yoga gentest
+// fixtures. Every top-level <div> is a separate tree.
+//
+// The style attribute is parsed like ParseStyle, except that NodePrinter
+// spellings are accepted ("flexWrap", "flex" is Style.Flex). The layout
+// attribute printed with PrintOptionsLayout is stored in Layout of the node,
+// so it can be compared with the result of CalculateLayout. The dir attribute
+// sets Style.Direction.
+func ParseHTML(html string, options HTMLOptions) ([]HTMLTree, error) {
+	p := &htmlParser{
+		src:     html,
+		options: options,
+		config:  options.Config,
+	}
+	if p.config == nil {
+		p.config = &configDefaults
+	}
+	var trees []HTMLTree
+	for {
+		if err := p.skipSpaceAndComments(); err != nil {
+			return nil, err
+		}
+		if p.pos == len(p.src) {
+			return trees, nil
+		}
+		if strings.HasPrefix(p.src[p.pos:], "</") {
+			return nil, p.errorf(p.pos, "unexpected closing tag")
+		}
+		root, id, err := p.parseElement()
+		if err != nil {
+			return nil, err
+		}
+		trees = append(trees, HTMLTree{ID: id, Root: root})
+	}
+}
+
+// ParseHTMLNode is like ParseHTML for HTML with exactly one tree
+func ParseHTMLNode(html string, options HTMLOptions) (*Node, error) {
+	trees, err := ParseHTML(html, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(trees) != 1 {
+		return nil, &HTMLParseError{Line: 1, Column: 1, Msg: fmt.Sprintf("expected 1 tree, got %d", len(trees))}
+	}
+	return trees[0].Root, nil
+}
+
+type htmlParser struct {
+	src     string
+	pos     int
+	options HTMLOptions
+	config  *Config
+}
+
+func (p *htmlParser) errorf(pos int, format string, args ...interface{}) error {
+	line, col := textPosition(p.src, pos)
+	return &HTMLParseError{
+		Offset: pos,
+		Line:   line,
+		Column: col,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *htmlParser) skipSpace() {
+	for p.pos < len(p.src) && isCSSSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *htmlParser) skipSpaceAndComments() error {
+	for {
+		p.skipSpace()
+		if !strings.HasPrefix(p.src[p.pos:], "<!--") {
+			return nil
+		}
+		end := strings.Index(p.src[p.pos+4:], "-->")
+		if end == -1 {
+			return p.errorf(p.pos, "unterminated comment")
+		}
+		p.pos += 4 + end + 3
+	}
+}
+
+func isHTMLNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == ':'
+}
+
+func (p *htmlParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.src) && isHTMLNameChar(p.src[p.pos]) {
+		p.pos++
+	}
+	return strings.ToLower(p.src[start:p.pos])
+}
+
+type htmlAttr struct {
+	name     string
+	value    string
+	valuePos int
+}
+
+// parseElement parses <div ...>children</div> starting at '<'
+func (p *htmlParser) parseElement() (*Node, string, error) {
+	start := p.pos
+	if p.src[p.pos] != '<' {
+		return nil, "", p.errorf(p.pos, "unexpected text")
+	}
+	p.pos++
+	tag := p.parseName()
+	if tag != "div" {
+		return nil, "", p.errorf(start, "unsupported element <%s>", tag)
+	}
+
+	attrs, selfClosing, err := p.parseAttributes()
+	if err != nil {
+		return nil, "", err
+	}
+
+	node := NewNodeWithConfig(p.config)
+	id := ""
+	hasMeasure := false
+	for _, attr := range attrs {
+		switch attr.name {
+		case "id":
+			id = attr.value
+		case "style":
+			err = p.parseStyle(node, attr)
+		case "layout":
+			err = p.parseLayout(node, attr)
+		case "dir":
+			err = p.parseDir(node, attr)
+		case "has-custom-measure":
+			hasMeasure = attr.value == "true"
+		}
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if !selfClosing {
+		if err := p.parseChildren(node, start); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if hasMeasure {
+		if p.options.MeasureFunc == nil {
+			return nil, "", p.errorf(start, "node has custom measure function, set HTMLOptions.MeasureFunc")
+		}
+		if err := node.TrySetMeasureFunc(p.options.MeasureFunc); err != nil {
+			return nil, "", p.errorf(start, "%s", err)
+		}
+	}
+	nodeMarkDirtyInternal(node)
+	return node, id, nil
+}
+
+// parseAttributes parses attributes up to and including '>' or '/>'
+func (p *htmlParser) parseAttributes() ([]htmlAttr, bool, error) {
+	var attrs []htmlAttr
+	for {
+		p.skipSpace()
+		if p.pos == len(p.src) {
+			return nil, false, p.errorf(p.pos, "unexpected end of input in tag")
+		}
+		if p.src[p.pos] == '>' {
+			p.pos++
+			return attrs, false, nil
+		}
+		if strings.HasPrefix(p.src[p.pos:], "/>") {
+			p.pos += 2
+			return attrs, true, nil
+		}
+		namePos := p.pos
+		name := p.parseName()
+		if name == "" {
+			return nil, false, p.errorf(namePos, "unexpected %q in tag", p.src[p.pos])
+		}
+		attr := htmlAttr{name: name, valuePos: p.pos}
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '=' {
+			p.pos++
+			p.skipSpace()
+			if p.pos == len(p.src) {
+				return nil, false, p.errorf(p.pos, "missing value of attribute %s", name)
+			}
+			quote := p.src[p.pos]
+			if quote == '"' || quote == '\'' {
+				end := strings.IndexByte(p.src[p.pos+1:], quote)
+				if end == -1 {
+					return nil, false, p.errorf(p.pos, "unterminated value of attribute %s", name)
+				}
+				attr.valuePos = p.pos + 1
+				attr.value = p.src[p.pos+1 : p.pos+1+end]
+				p.pos += end + 2
+			} else {
+				attr.valuePos = p.pos
+				for p.pos < len(p.src) && !isCSSSpace(p.src[p.pos]) && p.src[p.pos] != '>' {
+					p.pos++
+				}
+				attr.value = p.src[attr.valuePos:p.pos]
+			}
+		}
+		attrs = append(attrs, attr)
+	}
+}
+
+// parseChildren parses child elements up to and including </div>
+func (p *htmlParser) parseChildren(node *Node, start int) error {
+	for {
+		if err := p.skipSpaceAndComments(); err != nil {
+			return err
+		}
+		if p.pos == len(p.src) {
+			return p.errorf(start, "missing </div>")
+		}
+		if strings.HasPrefix(p.src[p.pos:], "</") {
+			closePos := p.pos
+			p.pos += 2
+			if tag := p.parseName(); tag != "div" {
+				return p.errorf(closePos, "expected </div>")
+			}
+			p.skipSpace()
+			if p.pos == len(p.src) || p.src[p.pos] != '>' {
+				return p.errorf(closePos, "expected </div>")
+			}
+			p.pos++
+			return nil
+		}
+		child, _, err := p.parseElement()
+		if err != nil {
+			return err
+		}
+		node.InsertChild(child, len(node.Children))
+	}
+}
+
+func (p *htmlParser) parseStyle(node *Node, attr htmlAttr) error {
+	css := &cssParser{src: attr.value, nodePrinter: true}
+	err := css.parse(&node.Style)
+	if e, ok := err.(*StyleParseError); ok {
+		return p.errorf(attr.valuePos+e.Offset, "%s", e.Msg)
+	}
+	return err
+}
+
+// parseLayout parses layout attribute printed by NodePrinter:
+// "width: 100; height: 100; top: 0; left: 0;"
+func (p *htmlParser) parseLayout(node *Node, attr htmlAttr) error {
+	start := 0
+	for _, decl := range strings.Split(attr.value, ";") {
+		pos := attr.valuePos + start + len(decl) - len(strings.TrimLeft(decl, " \t\r\n"))
+		start += len(decl) + 1
+		decl = strings.TrimSpace(decl)
+		if decl == "" {
+			continue
+		}
+		parts := strings.SplitN(decl, ":", 2)
+		if len(parts) != 2 {
+			return p.errorf(pos, "expected ':' in layout")
+		}
+		name := strings.TrimSpace(parts[0])
+		value := strings.TrimSuffix(strings.TrimSpace(parts[1]), "px")
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			valuePos := pos + len(parts[0]) + 1 + len(parts[1]) - len(strings.TrimLeft(parts[1], " \t\r\n"))
+			return p.errorf(valuePos, "invalid number %q", strings.TrimSpace(parts[1]))
+		}
+		switch name {
+		case "width":
+			node.Layout.Dimensions[DimensionWidth] = float32(f)
+		case "height":
+			node.Layout.Dimensions[DimensionHeight] = float32(f)
+		case "top":
+			node.Layout.Position[EdgeTop] = float32(f)
+		case "left":
+			node.Layout.Position[EdgeLeft] = float32(f)
+		default:
+			return p.errorf(pos, "unknown layout property %q", name)
+		}
+	}
+	return nil
+}
+
+func (p *htmlParser) parseDir(node *Node, attr htmlAttr) error {
+	direction, err := ParseDirection(strings.ToLower(attr.value))
+	if err != nil {
+		return p.errorf(attr.valuePos, "invalid dir %q", attr.value)
+	}
+	node.Style.Direction = direction
+	return nil
+}
@@ -0,0 +1,129 @@
+package flex
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const htmlFixtures = `
+<!-- from yoga/gentest/fixtures/YGJustifyContentTest.html -->
+<div id="justify_content_row_flex_start" style="width: 102px; height: 102px; flex-direction: row;">
+  <div style="width: 10px;"></div>
+  <div style="width: 10px;"></div>
+  <div style="width: 10px;"></div>
+</div>
+
+<div id="justify_content_row_flex_end_rtl" dir="rtl" style="width: 102px; height: 102px; flex-direction: row; justify-content: flex-end;">
+  <div style="width: 10px;"/>
+  <div style="width: 10px;"></div>
+</div>
+`
+
+func TestParse_html_fixtures(t *testing.T) {
+	trees, err := ParseHTML(htmlFixtures, HTMLOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(trees))
+
+	assert.Equal(t, "justify_content_row_flex_start", trees[0].ID)
+	root := trees[0].Root
+	assert.Equal(t, 3, len(root.Children))
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 102, root.LayoutGetWidth())
+	assertFloatEqual(t, 102, root.LayoutGetHeight())
+	for i, child := range root.Children {
+		assert.Equal(t, root, child.Parent)
+		assertFloatEqual(t, float32(10*i), child.LayoutGetLeft())
+		assertFloatEqual(t, 10, child.LayoutGetWidth())
+		assertFloatEqual(t, 102, child.LayoutGetHeight())
+	}
+
+	assert.Equal(t, "justify_content_row_flex_end_rtl", trees[1].ID)
+	root = trees[1].Root
+	assert.Equal(t, DirectionRTL, root.Style.Direction)
+	assert.Equal(t, 2, len(root.Children))
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 10, root.GetChild(0).LayoutGetLeft())
+	assertFloatEqual(t, 0, root.GetChild(1).LayoutGetLeft())
+}
+
+func printNode(node *Node) string {
+	var buf bytes.Buffer
+	NewNodePrinter(&buf, PrintOptionsLayout|PrintOptionsStyle|PrintOptionsChildren).Print(node)
+	return buf.String()
+}
+
+func TestParse_html_node_printer(t *testing.T) {
+	root := NewNode()
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetFlexWrap(WrapWrap)
+	root.StyleSetWidth(100)
+	root.StyleSetPadding(EdgeAll, 5)
+	root.StyleSetGap(GutterColumn, 4)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetFlex(1)
+	rootChild0.StyleSetMargin(EdgeLeft, 3)
+	rootChild0.StyleSetMargin(EdgeTop, 2)
+	rootChild0.StyleSetHeightPercent(50)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNode()
+	rootChild1.SetMeasureFunc(measureFixed)
+	rootChild1.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild1.StyleSetPosition(EdgeRight, 7)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, 40, DirectionLTR)
+
+	printed := printNode(root)
+	parsed, err := ParseHTMLNode(printed, HTMLOptions{MeasureFunc: measureFixed})
+	assert.NoError(t, err)
+	assert.True(t, styleEq(&root.Style, &parsed.Style))
+	assert.NotNil(t, parsed.GetChild(1).Measure)
+	for i, child := range root.Children {
+		assert.True(t, styleEq(&child.Style, &parsed.Children[i].Style))
+	}
+
+	// layout attribute is kept until the tree is laid out again
+	assert.Equal(t, printed, printNode(parsed))
+	CalculateLayout(parsed, Undefined, 40, DirectionLTR)
+	assert.Equal(t, printed, printNode(parsed))
+}
+
+func TestParse_html_errors(t *testing.T) {
+	tests := []struct {
+		html   string
+		line   int
+		column int
+		msg    string
+	}{
+		{`<span></span>`, 1, 1, "unsupported element <span>"},
+		{`<div>`, 1, 1, "missing </div>"},
+		{"<div>\n  <div></span>\n</div>", 2, 8, "expected </div>"},
+		{`</div>`, 1, 1, "unexpected closing tag"},
+		{`<div>text</div>`, 1, 6, "unexpected text"},
+		{`<div style="width`, 1, 12, "unterminated value of attribute style"},
+		{"<div\n style=\"width: 10px; colour: red\"></div>", 2, 22, `unknown property "colour"`},
+		{`<div layout="width: 10; top: x"></div>`, 1, 30, `invalid number "x"`},
+		{`<div layout="width: 10; right: 1"></div>`, 1, 25, `unknown layout property "right"`},
+		{`<div dir="up"></div>`, 1, 11, `invalid dir "up"`},
+		{`<div has-custom-measure="true"></div>`, 1, 1, "node has custom measure function, set HTMLOptions.MeasureFunc"},
+		{`<!-- <div>`, 1, 1, "unterminated comment"},
+	}
+	for _, test := range tests {
+		_, err := ParseHTML(test.html, HTMLOptions{})
+		if assert.Error(t, err, test.html) {
+			perr, ok := err.(*HTMLParseError)
+			assert.True(t, ok, test.html)
+			assert.Equal(t, test.line, perr.Line, test.html)
+			assert.Equal(t, test.column, perr.Column, test.html)
+			assert.Equal(t, test.msg, perr.Msg, test.html)
+		}
+	}
+
+	_, err := ParseHTMLNode(`<div></div><div></div>`, HTMLOptions{})
+	assert.EqualError(t, err, "1:1: expected 1 tree, got 2")
+}
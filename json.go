@@ -0,0 +1,399 @@
+package flex
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MeasureFuncRegistry maps names to measure functions. Functions can't be
+// serialized so JSON of a node refers to its measure function by name
+type MeasureFuncRegistry struct {
+	funcs map[string]MeasureFunc
+}
+
+// NewMeasureFuncRegistry creates an empty registry
+func NewMeasureFuncRegistry() *MeasureFuncRegistry {
+	return &MeasureFuncRegistry{
+		funcs: map[string]MeasureFunc{},
+	}
+}
+
+// Register registers measureFunc as name
+func (registry *MeasureFuncRegistry) Register(name string, measureFunc MeasureFunc) {
+	registry.funcs[name] = measureFunc
+}
+
+// Get returns measure function registered as name or nil
+func (registry *MeasureFuncRegistry) Get(name string) MeasureFunc {
+	if registry == nil {
+		return nil
+	}
+	return registry.funcs[name]
+}
+
+// SetMeasureFunc sets measure function registered as name on node and
+// remembers the name so that node can be marshaled to JSON
+func (registry *MeasureFuncRegistry) SetMeasureFunc(node *Node, name string) error {
+	measureFunc := registry.Get(name)
+	if measureFunc == nil {
+		return fmt.Errorf("%w: %q", ErrUnknownMeasureFunc, name)
+	}
+	if err := node.TrySetMeasureFunc(measureFunc); err != nil {
+		return err
+	}
+	node.measureFuncName = name
+	return nil
+}
+
+// MeasureFuncName returns the name of measure function set with
+// MeasureFuncRegistry.SetMeasureFunc
+func (node *Node) MeasureFuncName() string {
+	return node.measureFuncName
+}
+
+// JSONOptions are options for MarshalNodeJSON and UnmarshalNodeJSON
+type JSONOptions struct {
+	// Layout includes computed layout in marshaled JSON
+	Layout bool
+	// Indent indents marshaled JSON, like json.MarshalIndent
+	Indent string
+	// Config is used for unmarshaled nodes. Default config if nil
+	Config *Config
+	// MeasureFuncs resolves measure functions of unmarshaled nodes
+	MeasureFuncs *MeasureFuncRegistry
+}
+
+// MarshalNodeJSON returns JSON of node and its children. Only style
+// properties that differ from defaults are included:
+//
+//	{
+//	  "style": {"flex-direction": "row", "width": "100px", "flex-grow": 1},
+//	  "measure": "text",
+//	  "layout": {"left": 0, "top": 0, "width": 100, "height": 20, "direction": "ltr"},
+//	  "children": [...]
+//	}
+func MarshalNodeJSON(node *Node, options JSONOptions) ([]byte, error) {
+	v, err := nodeToJSON(node, &options)
+	if err != nil {
+		return nil, err
+	}
+	if options.Indent != "" {
+		return json.MarshalIndent(v, "", options.Indent)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalNodeJSON creates a node tree from JSON returned by MarshalNodeJSON
+func UnmarshalNodeJSON(data []byte, options JSONOptions) (*Node, error) {
+	var v nodeJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	config := options.Config
+	if config == nil {
+		config = &configDefaults
+	}
+	return nodeFromJSON(&v, config, options.MeasureFuncs)
+}
+
+type nodeJSON struct {
+	Style    map[string]json.RawMessage `json:"style,omitempty"`
+	Measure  string                     `json:"measure,omitempty"`
+	NodeType NodeType                   `json:"node-type,omitempty"`
+	Layout   *layoutJSON                `json:"layout,omitempty"`
+	Children []*nodeJSON                `json:"children,omitempty"`
+}
+
+type layoutJSON struct {
+	Left      jsonFloat `json:"left"`
+	Top       jsonFloat `json:"top"`
+	Width     jsonFloat `json:"width"`
+	Height    jsonFloat `json:"height"`
+	Direction Direction `json:"direction"`
+}
+
+// jsonFloat is float32 that is null in JSON when undefined
+type jsonFloat float32
+
+func (f jsonFloat) MarshalJSON() ([]byte, error) {
+	if FloatIsUndefined(float32(f)) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(float32(f))
+}
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*f = jsonFloat(Undefined)
+		return nil
+	}
+	var v float32
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*f = jsonFloat(v)
+	return nil
+}
+
+type textEnum interface {
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+}
+
+// styleProperty is a property of Style in JSON. Exactly one of enum,
+// float, value, tracks and placement is set
+type styleProperty struct {
+	name      string
+	enum      textEnum
+	float     *float32
+	value     *Value
+	tracks    *[]GridTrack
+	placement *GridPlacement
+}
+
+// styleProperties returns properties of style in the order they are
+// marshaled
+func styleProperties(style *Style) []styleProperty {
+	props := []styleProperty{
+		{name: "direction", enum: &style.Direction},
+		{name: "flex-direction", enum: &style.FlexDirection},
+		{name: "justify-content", enum: &style.JustifyContent},
+		{name: "align-content", enum: &style.AlignContent},
+		{name: "align-items", enum: &style.AlignItems},
+		{name: "align-self", enum: &style.AlignSelf},
+		{name: "position", enum: &style.PositionType},
+		{name: "flex-wrap", enum: &style.FlexWrap},
+		{name: "overflow", enum: &style.Overflow},
+		{name: "display", enum: &style.Display},
+		{name: "flex", float: &style.Flex},
+		{name: "flex-grow", float: &style.FlexGrow},
+		{name: "flex-shrink", float: &style.FlexShrink},
+		{name: "flex-basis", value: &style.FlexBasis},
+	}
+	for edge := EdgeLeft; edge < EdgeCount; edge++ {
+		props = append(props,
+			styleProperty{name: "margin-" + EdgeToString(edge), value: &style.Margin[edge]},
+			styleProperty{name: cssInsetProperty(edge), value: &style.Position[edge]},
+			styleProperty{name: "padding-" + EdgeToString(edge), value: &style.Padding[edge]},
+			styleProperty{name: "border-" + EdgeToString(edge), value: &style.Border[edge]})
+	}
+	props = append(props,
+		styleProperty{name: "column-gap", value: &style.Gap[GutterColumn]},
+		styleProperty{name: "row-gap", value: &style.Gap[GutterRow]},
+		styleProperty{name: "gap", value: &style.Gap[GutterAll]},
+		styleProperty{name: "width", value: &style.Dimensions[DimensionWidth]},
+		styleProperty{name: "height", value: &style.Dimensions[DimensionHeight]},
+		styleProperty{name: "min-width", value: &style.MinDimensions[DimensionWidth]},
+		styleProperty{name: "min-height", value: &style.MinDimensions[DimensionHeight]},
+		styleProperty{name: "max-width", value: &style.MaxDimensions[DimensionWidth]},
+		styleProperty{name: "max-height", value: &style.MaxDimensions[DimensionHeight]},
+		styleProperty{name: "aspect-ratio", float: &style.AspectRatio},
+		styleProperty{name: "grid-template-columns", tracks: &style.GridTemplateColumns},
+		styleProperty{name: "grid-template-rows", tracks: &style.GridTemplateRows},
+		styleProperty{name: "grid-column", placement: &style.GridColumn},
+		styleProperty{name: "grid-row", placement: &style.GridRow})
+	return props
+}
+
+func (prop *styleProperty) equal(other *styleProperty) bool {
+	switch {
+	case prop.enum != nil:
+		a, _ := prop.enum.MarshalText()
+		b, _ := other.enum.MarshalText()
+		return string(a) == string(b)
+	case prop.float != nil:
+		return feq(*prop.float, *other.float)
+	case prop.tracks != nil:
+		return gridTracksEq(*prop.tracks, *other.tracks)
+	case prop.placement != nil:
+		return *prop.placement == *other.placement
+	}
+	return valueEq(*prop.value, *other.value)
+}
+
+func (prop *styleProperty) marshal() (json.RawMessage, error) {
+	switch {
+	case prop.enum != nil:
+		return json.Marshal(prop.enum)
+	case prop.float != nil:
+		return json.Marshal(jsonFloat(*prop.float))
+	case prop.tracks != nil:
+		return json.Marshal(gridTracksToString(*prop.tracks))
+	case prop.placement != nil:
+		return json.Marshal(gridPlacementToString(*prop.placement))
+	}
+	return json.Marshal(valueToString(*prop.value))
+}
+
+func (prop *styleProperty) unmarshal(data json.RawMessage) error {
+	switch {
+	case prop.enum != nil:
+		return json.Unmarshal(data, prop.enum)
+	case prop.float != nil:
+		return json.Unmarshal(data, (*jsonFloat)(prop.float))
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	switch {
+	case prop.tracks != nil:
+		return parseCSSValue(s, func(p *cssParser, args []cssToken) error {
+			return p.parseGridTracks(args, prop.tracks)
+		})
+	case prop.placement != nil:
+		return parseCSSValue(s, func(p *cssParser, args []cssToken) error {
+			return p.parseGridPlacement(args, prop.placement)
+		})
+	}
+	v, err := valueFromString(s)
+	if err != nil {
+		return err
+	}
+	*prop.value = v
+	return nil
+}
+
+// valueToString returns "10px", "50%", "auto" or "undefined"
+func valueToString(v Value) string {
+	switch v.Unit {
+	case UnitPoint:
+		return fmt.Sprintf("%gpx", v.Value)
+	case UnitPercent:
+		return fmt.Sprintf("%g%%", v.Value)
+	case UnitAuto:
+		return "auto"
+	}
+	return "undefined"
+}
+
+func valueFromString(s string) (Value, error) {
+	if s == "undefined" {
+		return undefinedValue, nil
+	}
+	var v Value
+	p := &cssParser{src: s}
+	if err := p.parseLength(cssToken{text: strings.ToLower(s)}, &v, cssAllowAuto|cssAllowPercent); err != nil {
+		return v, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// parseCSSValue parses s like a value of CSS property
+func parseCSSValue(s string, parse func(p *cssParser, args []cssToken) error) error {
+	p := &cssParser{src: s}
+	args := fields(s, 0, len(s))
+	if len(args) == 0 {
+		return fmt.Errorf("missing value")
+	}
+	for i := range args {
+		args[i].text = strings.ToLower(args[i].text)
+	}
+	return parse(p, args)
+}
+
+func nodeToJSON(node *Node, options *JSONOptions) (*nodeJSON, error) {
+	res := &nodeJSON{
+		NodeType: node.NodeType,
+	}
+
+	defaults := nodeDefaults.Style
+	defaultProps := styleProperties(&defaults)
+	for i, prop := range styleProperties(&node.Style) {
+		if prop.equal(&defaultProps[i]) {
+			continue
+		}
+		data, err := prop.marshal()
+		if err != nil {
+			return nil, err
+		}
+		if res.Style == nil {
+			res.Style = map[string]json.RawMessage{}
+		}
+		res.Style[prop.name] = data
+	}
+
+	if node.Measure != nil {
+		if node.measureFuncName == "" {
+			return nil, ErrMeasureFuncNotRegistered
+		}
+		res.Measure = node.measureFuncName
+		// implied by measure function
+		if res.NodeType == NodeTypeText {
+			res.NodeType = NodeTypeDefault
+		}
+	}
+
+	if options.Layout {
+		res.Layout = &layoutJSON{
+			Left:      jsonFloat(node.Layout.Position[EdgeLeft]),
+			Top:       jsonFloat(node.Layout.Position[EdgeTop]),
+			Width:     jsonFloat(node.Layout.Dimensions[DimensionWidth]),
+			Height:    jsonFloat(node.Layout.Dimensions[DimensionHeight]),
+			Direction: node.Layout.Direction,
+		}
+	}
+
+	for _, child := range node.Children {
+		v, err := nodeToJSON(child, options)
+		if err != nil {
+			return nil, err
+		}
+		res.Children = append(res.Children, v)
+	}
+	return res, nil
+}
+
+func nodeFromJSON(v *nodeJSON, config *Config, registry *MeasureFuncRegistry) (*Node, error) {
+	node := NewNodeWithConfig(config)
+
+	// missing properties have values of nodeDefaults, even with web defaults
+	style := nodeDefaults.Style
+	props := map[string]*styleProperty{}
+	for _, prop := range styleProperties(&style) {
+		prop := prop
+		props[prop.name] = &prop
+	}
+	for name, data := range v.Style {
+		prop := props[name]
+		if prop == nil {
+			return nil, fmt.Errorf("unknown style property %q", name)
+		}
+		if err := prop.unmarshal(data); err != nil {
+			return nil, fmt.Errorf("style property %q: %w", name, err)
+		}
+	}
+	node.Style = style
+
+	if v.Measure != "" {
+		if len(v.Children) != 0 {
+			return nil, ErrMeasuredNodeHasChildren
+		}
+		if err := registry.SetMeasureFunc(node, v.Measure); err != nil {
+			return nil, err
+		}
+	}
+	if v.NodeType != NodeTypeDefault {
+		node.NodeType = v.NodeType
+	}
+
+	if v.Layout != nil {
+		node.Layout.Position[EdgeLeft] = float32(v.Layout.Left)
+		node.Layout.Position[EdgeTop] = float32(v.Layout.Top)
+		node.Layout.Dimensions[DimensionWidth] = float32(v.Layout.Width)
+		node.Layout.Dimensions[DimensionHeight] = float32(v.Layout.Height)
+		node.Layout.Direction = v.Layout.Direction
+	}
+
+	for i, childJSON := range v.Children {
+		child, err := nodeFromJSON(childJSON, config, registry)
+		if err != nil {
+			return nil, err
+		}
+		node.InsertChild(child, i)
+	}
+	nodeMarkDirtyInternal(node)
+	return node, nil
+}
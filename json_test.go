@@ -0,0 +1,164 @@
+package flex
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func measureFixed(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+	return Size{Width: 10, Height: 10}
+}
+
+func newJSONTestTree(registry *MeasureFuncRegistry) *Node {
+	root := NewNode()
+	root.StyleSetFlexDirection(FlexDirectionRow)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(50)
+	root.StyleSetPadding(EdgeAll, 5)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetFlexGrow(1)
+	rootChild0.StyleSetMarginAuto(EdgeLeft)
+	rootChild0.StyleSetMaxWidthPercent(50)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNode()
+	registry.SetMeasureFunc(rootChild1, "fixed")
+	rootChild1.StyleSetAlignSelf(AlignCenter)
+	root.InsertChild(rootChild1, 1)
+	return root
+}
+
+func TestJSON_marshal(t *testing.T) {
+	registry := NewMeasureFuncRegistry()
+	registry.Register("fixed", measureFixed)
+	root := newJSONTestTree(registry)
+
+	d, err := MarshalNodeJSON(root, JSONOptions{})
+	assert.NoError(t, err)
+	exp := `{"style":{"flex-direction":"row","height":"50px","padding-all":"5px","width":"100px"},` +
+		`"children":[{"style":{"flex-grow":1,"margin-left":"auto","max-width":"50%"}},` +
+		`{"style":{"align-self":"center"},"measure":"fixed"}]}`
+	assert.Equal(t, exp, string(d))
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	d, err = MarshalNodeJSON(root.GetChild(1), JSONOptions{Layout: true})
+	assert.NoError(t, err)
+	exp = `{"style":{"align-self":"center"},"measure":"fixed","layout":{"left":85,"top":20,"width":10,"height":10,"direction":"ltr"}}`
+	assert.Equal(t, exp, string(d))
+
+	d, err = MarshalNodeJSON(NewNode(), JSONOptions{Layout: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"layout":{"left":0,"top":0,"width":null,"height":null,"direction":"inherit"}}`, string(d))
+}
+
+func TestJSON_round_trip(t *testing.T) {
+	registry := NewMeasureFuncRegistry()
+	registry.Register("fixed", measureFixed)
+	root := newJSONTestTree(registry)
+	root.StyleSetAspectRatio(2)
+	root.StyleSetGap(GutterColumn, 3)
+	root.StyleSetPositionPercent(EdgeStart, 10)
+	root.StyleSetBorder(EdgeTop, 1)
+	root.StyleSetFlex(1)
+
+	d, err := MarshalNodeJSON(root, JSONOptions{Indent: "  "})
+	assert.NoError(t, err)
+
+	root2, err := UnmarshalNodeJSON(d, JSONOptions{MeasureFuncs: registry})
+	assert.NoError(t, err)
+	assert.True(t, styleEq(&root.Style, &root2.Style))
+	assert.Equal(t, 2, len(root2.Children))
+	for i, child := range root.Children {
+		assert.True(t, styleEq(&child.Style, &root2.Children[i].Style))
+		assert.Equal(t, root2, root2.Children[i].Parent)
+	}
+	assert.NotNil(t, root2.GetChild(1).Measure)
+	assert.Equal(t, "fixed", root2.GetChild(1).MeasureFuncName())
+	assert.Equal(t, NodeTypeText, root2.GetChild(1).NodeType)
+
+	d2, err := MarshalNodeJSON(root2, JSONOptions{Indent: "  "})
+	assert.NoError(t, err)
+	assert.Equal(t, string(d), string(d2))
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	CalculateLayout(root2, Undefined, Undefined, DirectionLTR)
+	d, _ = MarshalNodeJSON(root, JSONOptions{Layout: true})
+	d2, _ = MarshalNodeJSON(root2, JSONOptions{Layout: true})
+	assert.Equal(t, string(d), string(d2))
+}
+
+func TestJSON_insets_use_css_names(t *testing.T) {
+	node := NewNode()
+	node.StyleSetPosition(EdgeLeft, 1)
+	node.StyleSetPositionPercent(EdgeStart, 10)
+	node.StyleSetPosition(EdgeAll, 3)
+
+	d, err := MarshalNodeJSON(node, JSONOptions{})
+	assert.NoError(t, err)
+	exp := `{"style":{"inset-all":"3px","left":"1px","start":"10%"}}`
+	assert.Equal(t, exp, string(d))
+
+	// the same names are accepted by ApplyStyle
+	node2 := NewNode()
+	assert.NoError(t, node2.ApplyStyle("left: 1px; start: 10%; inset-all: 3px"))
+	assert.True(t, styleEq(&node.Style, &node2.Style))
+}
+
+func TestJSON_unmarshal_layout(t *testing.T) {
+	d := []byte(`{"layout":{"left":1,"top":2,"width":3,"height":null,"direction":"rtl"}}`)
+	node, err := UnmarshalNodeJSON(d, JSONOptions{})
+	assert.NoError(t, err)
+	assertFloatEqual(t, 1, node.LayoutGetLeft())
+	assertFloatEqual(t, 2, node.LayoutGetTop())
+	assertFloatEqual(t, 3, node.LayoutGetWidth())
+	assert.True(t, FloatIsUndefined(node.LayoutGetHeight()))
+	assert.Equal(t, DirectionRTL, node.Layout.Direction)
+}
+
+func TestJSON_unmarshal_web_defaults(t *testing.T) {
+	config := NewConfig()
+	config.UseWebDefaults = true
+
+	node, err := UnmarshalNodeJSON([]byte(`{}`), JSONOptions{Config: config})
+	assert.NoError(t, err)
+	assert.Equal(t, config, node.Config)
+	assert.Equal(t, FlexDirectionColumn, node.Style.FlexDirection)
+}
+
+func TestJSON_errors(t *testing.T) {
+	node := NewNode()
+	node.SetMeasureFunc(measureFixed)
+	_, err := MarshalNodeJSON(node, JSONOptions{})
+	assert.Equal(t, ErrMeasureFuncNotRegistered, err)
+
+	registry := NewMeasureFuncRegistry()
+	registry.Register("fixed", measureFixed)
+	assert.NoError(t, registry.SetMeasureFunc(node, "fixed"))
+	// setting measure function directly forgets the name
+	node.SetMeasureFunc(measureFixed)
+	assert.Equal(t, "", node.MeasureFuncName())
+
+	err = registry.SetMeasureFunc(node, "text")
+	assert.True(t, errors.Is(err, ErrUnknownMeasureFunc))
+
+	_, err = UnmarshalNodeJSON([]byte(`{"measure":"fixed"}`), JSONOptions{})
+	assert.True(t, errors.Is(err, ErrUnknownMeasureFunc))
+
+	_, err = UnmarshalNodeJSON([]byte(`{"measure":"fixed","children":[{}]}`), JSONOptions{MeasureFuncs: registry})
+	assert.Equal(t, ErrMeasuredNodeHasChildren, err)
+
+	_, err = UnmarshalNodeJSON([]byte(`{"style":{"colour":"red"}}`), JSONOptions{})
+	assert.EqualError(t, err, `unknown style property "colour"`)
+
+	_, err = UnmarshalNodeJSON([]byte(`{"style":{"width":"10em"}}`), JSONOptions{})
+	assert.EqualError(t, err, `style property "width": invalid value "10em"`)
+
+	_, err = UnmarshalNodeJSON([]byte(`{"style":{"align-items":"middle"}}`), JSONOptions{})
+	assert.True(t, errors.Is(err, ErrInvalidEnumValue))
+
+	_, err = UnmarshalNodeJSON([]byte(`[]`), JSONOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLayoutChangedTestTree(config *Config) (root, a, a1, a2, b, b1 *Node) {
+	root = NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	a = NewNodeWithConfig(config)
+	a.StyleSetFlexDirection(FlexDirectionRow)
+	a.StyleSetHeight(50)
+	root.InsertChild(a, 0)
+
+	a1 = NewNodeWithConfig(config)
+	a1.StyleSetWidth(20)
+	a.InsertChild(a1, 0)
+
+	a2 = NewNodeWithConfig(config)
+	a2.StyleSetFlexGrow(1)
+	a.InsertChild(a2, 1)
+
+	b = NewNodeWithConfig(config)
+	b.StyleSetHeight(50)
+	root.InsertChild(b, 1)
+
+	b1 = NewNodeWithConfig(config)
+	b1.StyleSetHeight(10)
+	b.InsertChild(b1, 0)
+	return
+}
+
+func markLayoutSeen(node *Node) {
+	node.MarkLayoutSeen()
+	for _, child := range node.Children {
+		markLayoutSeen(child)
+	}
+}
+
+func TestLayoutChanged_has_new_layout(t *testing.T) {
+	config := NewConfig()
+	root, a, a1, a2, b, b1 := newLayoutChangedTestTree(config)
+	assert.True(t, root.HasNewLayout())
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	for _, node := range []*Node{root, a, a1, a2, b, b1} {
+		assert.True(t, node.HasNewLayout())
+	}
+	markLayoutSeen(root)
+	assert.False(t, root.HasNewLayout())
+
+	a1.StyleSetWidth(30)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.True(t, root.HasNewLayout())
+	assert.True(t, a.HasNewLayout())
+	assert.True(t, a1.HasNewLayout())
+	assert.True(t, a2.HasNewLayout())
+	// b was positioned again but its layout came from the cache
+	assert.True(t, b.HasNewLayout())
+	assert.False(t, b1.HasNewLayout())
+}
+
+func TestLayoutChanged_callback(t *testing.T) {
+	config := NewConfig()
+	var changed []*Node
+	config.OnLayoutChanged = func(node *Node) {
+		changed = append(changed, node)
+	}
+	root, a, a1, a2, b, b1 := newLayoutChangedTestTree(config)
+
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []*Node{root, a, a1, a2, b, b1}, changed)
+
+	changed = nil
+	a1.StyleSetWidth(30)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []*Node{a1, a2}, changed)
+
+	// size changed before rounding only
+	changed = nil
+	a1.StyleSetWidth(30.2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 0, len(changed))
+
+	changed = nil
+	CalculateLayout(root, 200, Undefined, DirectionLTR)
+	assert.Equal(t, 0, len(changed))
+
+	changed = nil
+	root.StyleSetWidth(120)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []*Node{root, a, a2, b, b1}, changed)
+}
+
+func TestLayoutChanged_first_empty_layout(t *testing.T) {
+	config := NewConfig()
+	var changed []*Node
+	config.OnLayoutChanged = func(node *Node) {
+		changed = append(changed, node)
+	}
+	root := NewNodeWithConfig(config)
+	spacer := NewNodeWithConfig(config)
+	root.InsertChild(spacer, 0)
+
+	// 0x0 at the origin is still a new frame
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []*Node{root, spacer}, changed)
+
+	changed = nil
+	root.RemoveChild(spacer)
+	root.Reset()
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, []*Node{root}, changed)
+}
@@ -0,0 +1,160 @@
+package flex
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logEntry struct {
+	level LogLevel
+	node  *Node
+	msg   string
+}
+
+type testLogger struct {
+	entries []logEntry
+}
+
+func (l *testLogger) log(config *Config, node *Node, level LogLevel, format string, args ...interface{}) int {
+	msg := fmt.Sprintf(format, args...)
+	l.entries = append(l.entries, logEntry{level: level, node: node, msg: msg})
+	return len(msg)
+}
+
+func TestLogger_assert_logs_fatal(t *testing.T) {
+	logger := &testLogger{}
+	config := NewConfig()
+	config.Logger = logger.log
+
+	root := NewNodeWithConfig(config)
+	root.InsertChild(NewNodeWithConfig(config), 0)
+
+	assert.Panics(t, func() {
+		root.Reset()
+	})
+	assert.Equal(t, 1, len(logger.entries))
+	assert.Equal(t, LogLevelFatal, logger.entries[0].level)
+	assert.Equal(t, root, logger.entries[0].node)
+	assert.Equal(t, "Cannot reset a node which still has children attached\n", logger.entries[0].msg)
+}
+
+func TestLogger_assert_with_config_logs_fatal(t *testing.T) {
+	logger := &testLogger{}
+	config := NewConfig()
+	config.Logger = logger.log
+
+	assert.Panics(t, func() {
+		config.SetPointScaleFactor(-1)
+	})
+	assert.Equal(t, 1, len(logger.entries))
+	assert.Equal(t, LogLevelFatal, logger.entries[0].level)
+}
+
+func TestLogger_print_changes(t *testing.T) {
+	logger := &testLogger{}
+	config := NewConfig()
+	config.Logger = logger.log
+	config.Tracer = &LogTracer{PrintChanges: true, PrintSkips: true}
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	root.InsertChild(NewNodeWithConfig(config), 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.NotEmpty(t, logger.entries)
+	for _, e := range logger.entries {
+		assert.Equal(t, LogLevelVerbose, e.level)
+	}
+	assert.True(t, strings.HasPrefix(logger.entries[0].msg, " 1.{*"))
+}
+
+func TestLogger_print_tree(t *testing.T) {
+	logger := &testLogger{}
+	config := NewConfig()
+	config.Logger = logger.log
+	config.Tracer = &LogTracer{PrintTree: true}
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.Equal(t, 1, len(logger.entries))
+	assert.Equal(t, LogLevelDebug, logger.entries[0].level)
+	exp := `<div layout="width: 100; height: 100; top: 0; left: 0;" style="width: 100px; height: 100px; "></div>` + "\n"
+	assert.Equal(t, exp, logger.entries[0].msg)
+}
+
+func TestLogger_slog(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: SlogLevelVerbose,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	config := NewConfig()
+	config.Logger = NewSlogLogger(handler)
+
+	node := NewNodeWithConfig(config)
+	node.Context = "root"
+	log(node, LogLevelWarn, "hello %d\n", 5)
+	log(node, LogLevelFatal, "bye\n")
+	exp := "level=WARN msg=\"hello 5\" node=root\nlevel=ERROR+4 msg=bye node=root\n"
+	assert.Equal(t, exp, buf.String())
+}
+
+func TestLogger_slog_record_per_event(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: SlogLevelVerbose})
+	config := NewConfig()
+	config.Logger = NewSlogLogger(handler)
+	tracer := &LogTracer{PrintChanges: true, PrintSkips: true}
+	events := 0
+	config.Tracer = LayoutTracerFunc(func(event LayoutEvent) {
+		if event.Type != LayoutEventDone {
+			events++
+		}
+		tracer.TraceLayout(event)
+	})
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+	root.InsertChild(NewNodeWithConfig(config), 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	records := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Equal(t, events, len(records))
+	for _, record := range records {
+		// the depth and the measure modes are in the same record
+		assert.Regexp(t, `msg=" *\d\.[{}]\*?(\[skipped\] )?wm: `, record)
+	}
+}
+
+func TestLogger_slog_disabled_level(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+	logger := NewSlogLogger(handler)
+	n := logger(NewConfig(), nil, LogLevelVerbose, "trace\n")
+	assert.Equal(t, 0, n)
+	assert.Equal(t, "", buf.String())
+}
+
+func TestSlogLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelError, SlogLevel(LogLevelError))
+	assert.Equal(t, slog.LevelWarn, SlogLevel(LogLevelWarn))
+	assert.Equal(t, slog.LevelInfo, SlogLevel(LogLevelInfo))
+	assert.Equal(t, slog.LevelDebug, SlogLevel(LogLevelDebug))
+	assert.True(t, SlogLevel(LogLevelVerbose) < slog.LevelDebug)
+	assert.True(t, SlogLevel(LogLevelFatal) > slog.LevelError)
+}
@@ -71,3 +71,17 @@ func fmodf(x, y float32) float32 {
 	res := math.Mod(float64(x), float64(y))
 	return float32(res)
 }
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
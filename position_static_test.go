@@ -0,0 +1,235 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newStaticTestTree(parentPositionType PositionType) (*Node, *Node, *Node) {
+	root := NewNode()
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(200)
+	root.StyleSetPadding(EdgeAll, 10)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetPositionType(parentPositionType)
+	rootChild0.StyleSetMargin(EdgeLeft, 50)
+	rootChild0.StyleSetMargin(EdgeTop, 30)
+	rootChild0.StyleSetWidth(100)
+	rootChild0.StyleSetHeight(100)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNode()
+	rootChild0Child0.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild0Child0.StyleSetPosition(EdgeLeft, 10)
+	rootChild0Child0.StyleSetPosition(EdgeTop, 20)
+	rootChild0Child0.StyleSetWidthPercent(50)
+	rootChild0Child0.StyleSetHeight(20)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+	return root, rootChild0, rootChild0Child0
+}
+
+func TestPosition_static_absolute_child_uses_containing_block(t *testing.T) {
+	root, rootChild0, rootChild0Child0 := newStaticTestTree(PositionTypeStatic)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 60, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild0.LayoutGetTop())
+
+	// left: 10 and top: 20 from root, width: 50% of root
+	assertFloatEqual(t, -50, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, -20, rootChild0Child0.LayoutGetTop())
+	assertFloatEqual(t, 90, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0Child0.LayoutGetHeight())
+}
+
+func TestPosition_relative_absolute_child_uses_parent(t *testing.T) {
+	root, rootChild0, rootChild0Child0 := newStaticTestTree(PositionTypeRelative)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 60, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 40, rootChild0.LayoutGetTop())
+
+	assertFloatEqual(t, 10, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 20, rootChild0Child0.LayoutGetTop())
+	assertFloatEqual(t, 50, rootChild0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 20, rootChild0Child0.LayoutGetHeight())
+}
+
+func TestPosition_static_trailing_insets(t *testing.T) {
+	root, _, rootChild0Child0 := newStaticTestTree(PositionTypeStatic)
+	rootChild0Child0.StyleSetPosition(EdgeLeft, Undefined)
+	rootChild0Child0.StyleSetPosition(EdgeTop, Undefined)
+	rootChild0Child0.StyleSetPosition(EdgeRight, 10)
+	rootChild0Child0.StyleSetPosition(EdgeBottom, 10)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 40, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 130, rootChild0Child0.LayoutGetTop())
+	assertFloatEqual(t, 90, rootChild0Child0.LayoutGetWidth())
+
+	// trailing insets follow the containing block
+	root.StyleSetWidth(300)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 90, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 140, rootChild0Child0.LayoutGetWidth())
+}
+
+func TestPosition_static_without_insets(t *testing.T) {
+	root, rootChild0, rootChild0Child0 := newStaticTestTree(PositionTypeStatic)
+	rootChild0.StyleSetPadding(EdgeAll, 5)
+	rootChild0Child0.StyleSetPosition(EdgeLeft, Undefined)
+	rootChild0Child0.StyleSetPosition(EdgeTop, Undefined)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 5, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 5, rootChild0Child0.LayoutGetTop())
+	assertFloatEqual(t, 90, rootChild0Child0.LayoutGetWidth())
+}
+
+func TestPosition_static_ignores_insets(t *testing.T) {
+	root := NewNode()
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetPositionType(PositionTypeStatic)
+	rootChild0.StyleSetPosition(EdgeLeft, 10)
+	rootChild0.StyleSetPosition(EdgeTop, 10)
+	rootChild0.StyleSetHeight(10)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild1 := NewNode()
+	rootChild1.StyleSetPosition(EdgeLeft, 10)
+	rootChild1.StyleSetPosition(EdgeTop, 10)
+	rootChild1.StyleSetHeight(10)
+	root.InsertChild(rootChild1, 1)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 0, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0.LayoutGetTop())
+	assertFloatEqual(t, 10, rootChild1.LayoutGetLeft())
+	assertFloatEqual(t, 20, rootChild1.LayoutGetTop())
+}
+
+func TestPosition_static_nested(t *testing.T) {
+	root := NewNode()
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetPositionType(PositionTypeStatic)
+	rootChild0.StyleSetMargin(EdgeAll, 10)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNode()
+	rootChild0Child0.StyleSetPositionType(PositionTypeStatic)
+	rootChild0Child0.StyleSetMargin(EdgeAll, 5)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	rootChild0Child0Child0 := NewNode()
+	rootChild0Child0Child0.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild0Child0Child0.StyleSetPosition(EdgeLeft, 0)
+	rootChild0Child0Child0.StyleSetPosition(EdgeTop, 0)
+	rootChild0Child0Child0.StyleSetPosition(EdgeRight, 0)
+	rootChild0Child0Child0.StyleSetPosition(EdgeBottom, 0)
+	rootChild0Child0.InsertChild(rootChild0Child0Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 5, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 5, rootChild0Child0.LayoutGetTop())
+
+	assertFloatEqual(t, -15, rootChild0Child0Child0.LayoutGetLeft())
+	assertFloatEqual(t, -15, rootChild0Child0Child0.LayoutGetTop())
+	assertFloatEqual(t, 100, rootChild0Child0Child0.LayoutGetWidth())
+	assertFloatEqual(t, 100, rootChild0Child0Child0.LayoutGetHeight())
+}
+
+func TestPosition_static_rtl(t *testing.T) {
+	root := NewNode()
+	root.StyleSetWidth(200)
+	root.StyleSetHeight(200)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetPositionType(PositionTypeStatic)
+	rootChild0.StyleSetAlignSelf(AlignFlexStart)
+	rootChild0.StyleSetWidth(100)
+	rootChild0.StyleSetHeight(100)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNode()
+	rootChild0Child0.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild0Child0.StyleSetPosition(EdgeStart, 10)
+	rootChild0Child0.StyleSetWidth(20)
+	rootChild0Child0.StyleSetHeight(20)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionRTL)
+
+	assertFloatEqual(t, 100, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 70, rootChild0Child0.LayoutGetLeft())
+	assertFloatEqual(t, 0, rootChild0Child0.LayoutGetTop())
+}
+
+func TestPosition_type_static_default(t *testing.T) {
+	assert.Equal(t, PositionTypeRelative, NewNode().Style.PositionType)
+	assert.Equal(t, "static", PositionTypeToString(PositionTypeStatic))
+
+	style, err := ParseStyle("position: static")
+	assert.NoError(t, err)
+	assert.Equal(t, PositionTypeStatic, style.PositionType)
+}
+
+func TestPosition_static_root_is_containing_block(t *testing.T) {
+	root := NewNode()
+	root.StyleSetPositionType(PositionTypeStatic)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetPositionType(PositionTypeAbsolute)
+	rootChild0.StyleSetPosition(EdgeRight, 5)
+	rootChild0.StyleSetPosition(EdgeTop, 5)
+	rootChild0.StyleSetWidth(10)
+	rootChild0.StyleSetHeight(10)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assertFloatEqual(t, 85, rootChild0.LayoutGetLeft())
+	assertFloatEqual(t, 5, rootChild0.LayoutGetTop())
+}
+
+// static position of absolute child doesn't depend on position type of parent
+func TestPosition_static_without_insets_is_aligned(t *testing.T) {
+	for _, positionType := range []PositionType{PositionTypeRelative, PositionTypeStatic} {
+		root := NewNode()
+		root.StyleSetWidth(200)
+		root.StyleSetHeight(200)
+
+		rootChild0 := NewNode()
+		rootChild0.StyleSetPositionType(positionType)
+		rootChild0.StyleSetJustifyContent(JustifyCenter)
+		rootChild0.StyleSetAlignItems(AlignCenter)
+		rootChild0.StyleSetWidth(100)
+		rootChild0.StyleSetHeight(100)
+		root.InsertChild(rootChild0, 0)
+
+		rootChild0Child0 := NewNode()
+		rootChild0Child0.StyleSetPositionType(PositionTypeAbsolute)
+		rootChild0Child0.StyleSetWidth(10)
+		rootChild0Child0.StyleSetHeight(10)
+		rootChild0.InsertChild(rootChild0Child0, 0)
+		CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+		assertFloatEqual(t, 45, rootChild0Child0.LayoutGetLeft())
+		assertFloatEqual(t, 45, rootChild0Child0.LayoutGetTop())
+
+		rootChild0.StyleSetFlexDirection(FlexDirectionColumnReverse)
+		rootChild0.StyleSetJustifyContent(JustifyFlexStart)
+		rootChild0.StyleSetAlignItems(AlignFlexEnd)
+		CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+		assertFloatEqual(t, 90, rootChild0Child0.LayoutGetLeft())
+		assertFloatEqual(t, 90, rootChild0Child0.LayoutGetTop())
+	}
+}
@@ -1,9 +1,9 @@
 package flex
 
 import (
+	"bytes"
 	"fmt"
 	"io"
-	"os"
 	"strings"
 )
 
@@ -13,10 +13,13 @@ type NodePrinter struct {
 	options PrintOptions
 }
 
-// NodePrint prints node to standard output.
+// NodePrint prints node to node's Config.Logger at LogLevelDebug. With
+// DefaultLog this is standard output.
 func NodePrint(node *Node, options PrintOptions) {
-	printer := NewNodePrinter(os.Stdout, options)
+	var buf bytes.Buffer
+	printer := NewNodePrinter(&buf, options)
 	printer.Print(node)
+	log(node, LogLevelDebug, "%s\n", buf.String())
 }
 
 // NewNodePrinter creates new node printer.
@@ -90,6 +93,23 @@ func (printer *NodePrinter) printNode(node *Node, level int) {
 		printer.printEdges(node, "padding", node.Style.Padding[:])
 		printer.printEdges(node, "border", node.Style.Border[:])
 
+		printer.printNumberIfNotUndefined(node, "column-gap", &node.Style.Gap[GutterColumn])
+		printer.printNumberIfNotUndefined(node, "row-gap", &node.Style.Gap[GutterRow])
+		printer.printNumberIfNotUndefined(node, "gap", &node.Style.Gap[GutterAll])
+
+		if len(node.Style.GridTemplateColumns) != 0 {
+			printer.printf("grid-template-columns: %s; ", gridTracksToString(node.Style.GridTemplateColumns))
+		}
+		if len(node.Style.GridTemplateRows) != 0 {
+			printer.printf("grid-template-rows: %s; ", gridTracksToString(node.Style.GridTemplateRows))
+		}
+		if node.Style.GridColumn != nodeDefaults.Style.GridColumn {
+			printer.printf("grid-column: %s; ", gridPlacementToString(node.Style.GridColumn))
+		}
+		if node.Style.GridRow != nodeDefaults.Style.GridRow {
+			printer.printf("grid-row: %s; ", gridPlacementToString(node.Style.GridRow))
+		}
+
 		printer.printNumberIfNotAuto(node, "width", &node.Style.Dimensions[DimensionWidth])
 		printer.printNumberIfNotAuto(node, "height", &node.Style.Dimensions[DimensionHeight])
 		printer.printNumberIfNotAuto(node, "max-width", &node.Style.MaxDimensions[DimensionWidth])
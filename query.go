@@ -0,0 +1,134 @@
+package flex
+
+// Rect describes a rectangle
+type Rect struct {
+	Left   float32
+	Top    float32
+	Width  float32
+	Height float32
+}
+
+// Right returns right edge of r
+func (r Rect) Right() float32 {
+	return r.Left + r.Width
+}
+
+// Bottom returns bottom edge of r
+func (r Rect) Bottom() float32 {
+	return r.Top + r.Height
+}
+
+// Contains returns true if point x, y is inside r. Right and bottom edge
+// are outside
+func (r Rect) Contains(x float32, y float32) bool {
+	return x >= r.Left && x < r.Right() && y >= r.Top && y < r.Bottom()
+}
+
+// Intersects returns true if r and other overlap
+func (r Rect) Intersects(other Rect) bool {
+	return r.Left < other.Right() && other.Left < r.Right() &&
+		r.Top < other.Bottom() && other.Top < r.Bottom()
+}
+
+// Intersect returns the overlap of r and other, which is empty if they
+// don't intersect
+func (r Rect) Intersect(other Rect) Rect {
+	left := fmaxf(r.Left, other.Left)
+	top := fmaxf(r.Top, other.Top)
+	right := fmaxf(left, fminf(r.Right(), other.Right()))
+	bottom := fmaxf(top, fminf(r.Bottom(), other.Bottom()))
+	return Rect{Left: left, Top: top, Width: right - left, Height: bottom - top}
+}
+
+// AbsoluteFrame returns position and size of node relative to the root of
+// its tree
+func (node *Node) AbsoluteFrame() Rect {
+	frame := Rect{
+		Width:  node.Layout.Dimensions[DimensionWidth],
+		Height: node.Layout.Dimensions[DimensionHeight],
+	}
+	for n := node; n != nil; n = n.Parent {
+		frame.Left += n.Layout.Position[EdgeLeft]
+		frame.Top += n.Layout.Position[EdgeTop]
+	}
+	return frame
+}
+
+// nodeIsClipping returns true if node clips its descendants
+func nodeIsClipping(node *Node) bool {
+	return node.Style.Overflow != OverflowVisible
+}
+
+// HitTest returns the deepest node in tree of root that contains point x, y
+// in the coordinates of AbsoluteFrame. Of overlapping siblings the one that's
+// later in Children wins. Nodes with DisplayNone and their descendants are
+// skipped. If clip is true, descendants of nodes with OverflowHidden or
+// OverflowScroll are hit only inside of these nodes
+func HitTest(root *Node, x float32, y float32, clip bool) *Node {
+	frame := root.AbsoluteFrame()
+	return nodeHitTest(root, frame.Left-root.Layout.Position[EdgeLeft], frame.Top-root.Layout.Position[EdgeTop], x, y, clip)
+}
+
+// nodeHitTest hit tests node whose parent is at left, top
+func nodeHitTest(node *Node, left float32, top float32, x float32, y float32, clip bool) *Node {
+	if node.Style.Display == DisplayNone {
+		return nil
+	}
+	frame := Rect{
+		Left:   left + node.Layout.Position[EdgeLeft],
+		Top:    top + node.Layout.Position[EdgeTop],
+		Width:  node.Layout.Dimensions[DimensionWidth],
+		Height: node.Layout.Dimensions[DimensionHeight],
+	}
+	inside := frame.Contains(x, y)
+	if !inside && clip && nodeIsClipping(node) {
+		return nil
+	}
+	for i := len(node.Children) - 1; i >= 0; i-- {
+		if hit := nodeHitTest(node.Children[i], frame.Left, frame.Top, x, y, clip); hit != nil {
+			return hit
+		}
+	}
+	if inside && node.Style.Display != DisplayContents {
+		return node
+	}
+	return nil
+}
+
+// NodesInRect returns nodes in tree of root that intersect rect in the
+// coordinates of AbsoluteFrame, parents before their children. Nodes with
+// DisplayNone and their descendants are skipped. If clip is true, descendants
+// of nodes with OverflowHidden or OverflowScroll are returned only if they're
+// visible inside of these nodes
+func NodesInRect(root *Node, rect Rect, clip bool) []*Node {
+	frame := root.AbsoluteFrame()
+	var nodes []*Node
+	nodeNodesInRect(root, frame.Left-root.Layout.Position[EdgeLeft], frame.Top-root.Layout.Position[EdgeTop], rect, clip, &nodes)
+	return nodes
+}
+
+// nodeNodesInRect appends to nodes node and its descendants that intersect
+// rect. Parent of node is at left, top
+func nodeNodesInRect(node *Node, left float32, top float32, rect Rect, clip bool, nodes *[]*Node) {
+	if node.Style.Display == DisplayNone {
+		return
+	}
+	frame := Rect{
+		Left:   left + node.Layout.Position[EdgeLeft],
+		Top:    top + node.Layout.Position[EdgeTop],
+		Width:  node.Layout.Dimensions[DimensionWidth],
+		Height: node.Layout.Dimensions[DimensionHeight],
+	}
+	if frame.Intersects(rect) && node.Style.Display != DisplayContents {
+		*nodes = append(*nodes, node)
+	}
+	if clip && nodeIsClipping(node) {
+		rect = rect.Intersect(frame)
+		if rect.Width <= 0 || rect.Height <= 0 {
+			return
+		}
+	}
+	for _, child := range node.Children {
+		nodeNodesInRect(child, frame.Left, frame.Top, rect, clip, nodes)
+	}
+}
@@ -0,0 +1,70 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newQueryTestTree() (root, a, a1, b, c *Node) {
+	absolute := func(left, top, width, height float32) *Node {
+		return New(PositionTypeAbsolute, Position(EdgeLeft, left), Position(EdgeTop, top), Width(width), Height(height))
+	}
+	a1 = absolute(50, 50, 100, 100)
+	a = absolute(10, 10, 100, 100).Apply(OverflowHidden, Children(a1))
+	b = absolute(120, 120, 50, 50)
+	c = absolute(0, 0, 50, 50).Apply(DisplayNone)
+	root = New(Width(200), Height(200), Children(a, b, c))
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	return
+}
+
+func TestQuery_absolute_frame(t *testing.T) {
+	root, a, a1, b, _ := newQueryTestTree()
+
+	assert.Equal(t, Rect{Left: 0, Top: 0, Width: 200, Height: 200}, root.AbsoluteFrame())
+	assert.Equal(t, Rect{Left: 10, Top: 10, Width: 100, Height: 100}, a.AbsoluteFrame())
+	assert.Equal(t, Rect{Left: 60, Top: 60, Width: 100, Height: 100}, a1.AbsoluteFrame())
+	assert.Equal(t, Rect{Left: 120, Top: 120, Width: 50, Height: 50}, b.AbsoluteFrame())
+	assertFloatEqual(t, 170, b.AbsoluteFrame().Right())
+	assertFloatEqual(t, 170, b.AbsoluteFrame().Bottom())
+}
+
+func TestQuery_hit_test(t *testing.T) {
+	root, a, a1, b, _ := newQueryTestTree()
+
+	assert.Same(t, a1, HitTest(root, 95, 95, false))
+	assert.Same(t, b, HitTest(root, 130, 130, false))
+	assert.Same(t, a, HitTest(root, 20, 20, false))
+	assert.Same(t, root, HitTest(root, 5, 5, false))
+	assert.Nil(t, HitTest(root, 200, 5, false))
+
+	// a1 outside of a
+	assert.Same(t, a1, HitTest(root, 140, 100, false))
+	assert.Same(t, root, HitTest(root, 140, 100, true))
+	assert.Same(t, a1, HitTest(root, 95, 95, true))
+
+	// coordinates don't depend on the root of the query
+	assert.Same(t, a1, HitTest(a, 95, 95, false))
+	assert.Nil(t, HitTest(a, 5, 5, false))
+}
+
+func TestQuery_nodes_in_rect(t *testing.T) {
+	root, a, a1, b, _ := newQueryTestTree()
+
+	assert.Equal(t, []*Node{root, a, a1}, NodesInRect(root, Rect{Left: 100, Top: 100, Width: 20, Height: 20}, false))
+	assert.Equal(t, []*Node{root, a, a1}, NodesInRect(root, Rect{Left: 100, Top: 100, Width: 20, Height: 20}, true))
+	assert.Equal(t, []*Node{root, a1, b}, NodesInRect(root, Rect{Left: 130, Top: 130, Width: 10, Height: 10}, false))
+	assert.Equal(t, []*Node{root, b}, NodesInRect(root, Rect{Left: 130, Top: 130, Width: 10, Height: 10}, true))
+	assert.Equal(t, 0, len(NodesInRect(root, Rect{Left: 300, Top: 0, Width: 10, Height: 10}, false)))
+}
+
+func TestQuery_rect(t *testing.T) {
+	r := Rect{Left: 10, Top: 10, Width: 20, Height: 20}
+	assert.True(t, r.Contains(10, 10))
+	assert.False(t, r.Contains(30, 10))
+	assert.True(t, r.Intersects(Rect{Left: 29, Top: 29, Width: 5, Height: 5}))
+	assert.False(t, r.Intersects(Rect{Left: 30, Top: 10, Width: 5, Height: 5}))
+	assert.Equal(t, Rect{Left: 25, Top: 10, Width: 5, Height: 20}, r.Intersect(Rect{Left: 25, Top: 0, Width: 50, Height: 50}))
+	assert.Equal(t, Rect{Left: 40, Top: 10, Width: 0, Height: 20}, r.Intersect(Rect{Left: 40, Top: 0, Width: 50, Height: 50}))
+}
@@ -0,0 +1,57 @@
+package flex
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// slog has no levels matching LogLevelVerbose and LogLevelFatal so we
+// place them below slog.LevelDebug and above slog.LevelError
+const (
+	// SlogLevelVerbose is slog level used for LogLevelVerbose
+	SlogLevelVerbose = slog.LevelDebug - 4
+	// SlogLevelFatal is slog level used for LogLevelFatal
+	SlogLevelFatal = slog.LevelError + 4
+)
+
+// SlogLevel returns slog level corresponding to LogLevel
+func SlogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LogLevelError:
+		return slog.LevelError
+	case LogLevelWarn:
+		return slog.LevelWarn
+	case LogLevelInfo:
+		return slog.LevelInfo
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelVerbose:
+		return SlogLevelVerbose
+	case LogLevelFatal:
+		return SlogLevelFatal
+	}
+	return slog.LevelInfo
+}
+
+// NewSlogLogger returns a Logger that sends messages to a slog handler.
+// Each message becomes a separate record, with trailing newline removed.
+// If node has a Context, it's added as "node" attribute.
+func NewSlogLogger(handler slog.Handler) Logger {
+	return func(config *Config, node *Node, level LogLevel, format string, args ...interface{}) int {
+		ctx := context.Background()
+		slogLevel := SlogLevel(level)
+		if !handler.Enabled(ctx, slogLevel) {
+			return 0
+		}
+		msg := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+		record := slog.NewRecord(time.Now(), slogLevel, msg, 0)
+		if node != nil && node.Context != nil {
+			record.AddAttrs(slog.Any("node", node.Context))
+		}
+		handler.Handle(ctx, record)
+		return len(msg)
+	}
+}
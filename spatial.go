@@ -0,0 +1,255 @@
+package flex
+
+// SpatialIndex finds nodes of a tree that intersect a rectangle without
+// walking the whole tree. It's an interval tree over the vertical extent of
+// absolute frames of nodes, so it suits tall trees like long lists best.
+//
+// After CalculateLayout call Update. It visits only nodes that were laid out
+// or positioned since the previous Update and nodes that moved with their
+// parent. It doesn't reset HasNewLayout, which is left for the host
+type SpatialIndex struct {
+	root       *Node
+	entries    map[*Node]*spatialEntry
+	tree       *spatialEntry
+	generation int
+	seed       uint32
+	nextID     uint64
+}
+
+// spatialEntry is a node in the index and in the treap ordered by top of
+// frame
+type spatialEntry struct {
+	node     *Node
+	parent   *Node
+	children []*Node
+	frame    Rect
+	// indexed is false for nodes with DisplayContents, which have no frame
+	indexed    bool
+	generation int
+	// layoutVersion is layoutVersion of node when it was last visited
+	layoutVersion uint64
+
+	id       uint64
+	priority uint32
+	left     *spatialEntry
+	right    *spatialEntry
+	// maxBottom is the largest bottom of frames in the subtree
+	maxBottom float32
+}
+
+// NewSpatialIndex creates an index of root and its descendants
+func NewSpatialIndex(root *Node) *SpatialIndex {
+	index := &SpatialIndex{
+		root:    root,
+		entries: make(map[*Node]*spatialEntry),
+		seed:    2463534242,
+	}
+	index.Update()
+	return index
+}
+
+// Len returns the number of indexed nodes
+func (index *SpatialIndex) Len() int {
+	n := 0
+	for _, entry := range index.entries {
+		if entry.indexed {
+			n++
+		}
+	}
+	return n
+}
+
+// Update updates the index after layout of the tree changed
+func (index *SpatialIndex) Update() {
+	index.generation++
+	root := index.root
+	frame := root.AbsoluteFrame()
+	index.update(root, nil, frame.Left-root.Layout.Position[EdgeLeft], frame.Top-root.Layout.Position[EdgeTop], false)
+}
+
+// Query returns nodes whose absolute frame intersects rect, ordered by top
+// of their frame. Nodes with DisplayNone and their descendants aren't
+// indexed
+func (index *SpatialIndex) Query(rect Rect) []*Node {
+	var nodes []*Node
+	spatialQuery(index.tree, rect, &nodes)
+	return nodes
+}
+
+// update updates node whose parent is at left, top. moved is true if the
+// parent moved, so frames of all descendants changed
+func (index *SpatialIndex) update(node *Node, parent *Node, left float32, top float32, moved bool) {
+	entry := index.entries[node]
+	if entry != nil {
+		if entry.parent != parent {
+			moved = true
+		}
+		entry.generation = index.generation
+		if !moved && node.layoutVersion == entry.layoutVersion {
+			return
+		}
+	}
+
+	if node.Style.Display == DisplayNone {
+		if entry != nil {
+			index.removeTree(node, entry.parent)
+		}
+		return
+	}
+
+	frame := Rect{
+		Left:   left + node.Layout.Position[EdgeLeft],
+		Top:    top + node.Layout.Position[EdgeTop],
+		Width:  node.Layout.Dimensions[DimensionWidth],
+		Height: node.Layout.Dimensions[DimensionHeight],
+	}
+	indexed := node.Style.Display != DisplayContents
+	if entry == nil {
+		entry = &spatialEntry{node: node, generation: index.generation}
+		index.entries[node] = entry
+		moved = true
+	} else {
+		if entry.frame.Left != frame.Left || entry.frame.Top != frame.Top {
+			moved = true
+		}
+		if entry.indexed && (entry.frame != frame || !indexed) {
+			index.tree = spatialDelete(index.tree, entry)
+			entry.indexed = false
+		}
+	}
+	entry.parent = parent
+	entry.frame = frame
+	entry.layoutVersion = node.layoutVersion
+	if indexed && !entry.indexed {
+		index.insert(entry)
+	}
+
+	for _, child := range node.Children {
+		index.update(child, node, frame.Left, frame.Top, moved)
+	}
+	// remove children that were removed from node
+	for _, child := range entry.children {
+		if childEntry := index.entries[child]; childEntry != nil &&
+			childEntry.parent == node && childEntry.generation != index.generation {
+			index.removeTree(child, node)
+		}
+	}
+	entry.children = append(entry.children[:0], node.Children...)
+}
+
+// removeTree removes node with parent and its descendants from the index
+func (index *SpatialIndex) removeTree(node *Node, parent *Node) {
+	entry := index.entries[node]
+	if entry == nil || entry.parent != parent {
+		return
+	}
+	if entry.indexed {
+		index.tree = spatialDelete(index.tree, entry)
+	}
+	delete(index.entries, node)
+	for _, child := range entry.children {
+		index.removeTree(child, node)
+	}
+}
+
+// insert inserts entry into the treap
+func (index *SpatialIndex) insert(entry *spatialEntry) {
+	// xorshift is good enough for balancing the treap
+	index.seed ^= index.seed << 13
+	index.seed ^= index.seed >> 17
+	index.seed ^= index.seed << 5
+	index.nextID++
+	entry.priority = index.seed
+	entry.id = index.nextID
+	entry.left, entry.right = nil, nil
+	entry.maxBottom = entry.frame.Bottom()
+	entry.indexed = true
+
+	left, right := spatialSplit(index.tree, entry)
+	index.tree = spatialMerge(spatialMerge(left, entry), right)
+}
+
+// spatialLess orders entries by top of frame
+func spatialLess(a *spatialEntry, b *spatialEntry) bool {
+	if a.frame.Top != b.frame.Top {
+		return a.frame.Top < b.frame.Top
+	}
+	return a.id < b.id
+}
+
+func spatialUpdateMaxBottom(t *spatialEntry) {
+	t.maxBottom = t.frame.Bottom()
+	if t.left != nil {
+		t.maxBottom = fmaxf(t.maxBottom, t.left.maxBottom)
+	}
+	if t.right != nil {
+		t.maxBottom = fmaxf(t.maxBottom, t.right.maxBottom)
+	}
+}
+
+// spatialSplit splits t into entries before entry and the rest
+func spatialSplit(t *spatialEntry, entry *spatialEntry) (*spatialEntry, *spatialEntry) {
+	if t == nil {
+		return nil, nil
+	}
+	if spatialLess(t, entry) {
+		left, right := spatialSplit(t.right, entry)
+		t.right = left
+		spatialUpdateMaxBottom(t)
+		return t, right
+	}
+	left, right := spatialSplit(t.left, entry)
+	t.left = right
+	spatialUpdateMaxBottom(t)
+	return left, t
+}
+
+// spatialMerge merges a and b, all entries of a are before entries of b
+func spatialMerge(a *spatialEntry, b *spatialEntry) *spatialEntry {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.priority > b.priority {
+		a.right = spatialMerge(a.right, b)
+		spatialUpdateMaxBottom(a)
+		return a
+	}
+	b.left = spatialMerge(a, b.left)
+	spatialUpdateMaxBottom(b)
+	return b
+}
+
+// spatialDelete removes entry from t
+func spatialDelete(t *spatialEntry, entry *spatialEntry) *spatialEntry {
+	if t == nil {
+		return nil
+	}
+	if t == entry {
+		return spatialMerge(t.left, t.right)
+	}
+	if spatialLess(entry, t) {
+		t.left = spatialDelete(t.left, entry)
+	} else {
+		t.right = spatialDelete(t.right, entry)
+	}
+	spatialUpdateMaxBottom(t)
+	return t
+}
+
+// spatialQuery appends nodes of entries in t that intersect rect to nodes
+func spatialQuery(t *spatialEntry, rect Rect, nodes *[]*Node) {
+	if t == nil || t.maxBottom <= rect.Top {
+		return
+	}
+	spatialQuery(t.left, rect, nodes)
+	if t.frame.Top >= rect.Bottom() {
+		return
+	}
+	if t.frame.Intersects(rect) {
+		*nodes = append(*nodes, t.node)
+	}
+	spatialQuery(t.right, rect, nodes)
+}
@@ -0,0 +1,134 @@
+package flex
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSpatialTestFeed(count int) *Node {
+	root := New(Width(100))
+	for i := 0; i < count; i++ {
+		item := New(Height(10), Row, Children(New(Width(20), Margin(EdgeTop, 2), Height(5))))
+		root.InsertChild(item, i)
+	}
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	return root
+}
+
+func assertSpatialQuery(t *testing.T, index *SpatialIndex, root *Node, rect Rect) {
+	t.Helper()
+	assert.ElementsMatch(t, NodesInRect(root, rect, false), index.Query(rect))
+}
+
+func TestSpatialIndex_query(t *testing.T) {
+	root := newSpatialTestFeed(1000)
+	index := NewSpatialIndex(root)
+	assert.Equal(t, 2001, index.Len())
+
+	rect := Rect{Left: 0, Top: 500, Width: 100, Height: 25}
+	nodes := index.Query(rect)
+	assert.Equal(t, 7, len(nodes))
+	assert.Same(t, root, nodes[0])
+	assert.Same(t, root.GetChild(50), nodes[1])
+	assertSpatialQuery(t, index, root, rect)
+
+	rect = Rect{Left: 50, Top: 500, Width: 50, Height: 25}
+	assert.Equal(t, []*Node{root, root.GetChild(50), root.GetChild(51), root.GetChild(52)}, index.Query(rect))
+}
+
+func TestSpatialIndex_keeps_has_new_layout(t *testing.T) {
+	root := newSpatialTestFeed(100)
+	index := NewSpatialIndex(root)
+	assert.True(t, root.HasNewLayout())
+	assert.True(t, root.GetChild(10).HasNewLayout())
+
+	// the host reading layout doesn't hide changes from the index
+	root.GetChild(10).StyleSetHeight(30)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	markLayoutSeenRecursive(root)
+	index.Update()
+	assert.False(t, root.HasNewLayout())
+	assertSpatialQuery(t, index, root, Rect{Left: 0, Top: 500, Width: 100, Height: 25})
+
+	root.GetChild(20).StyleSetHeight(40)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	index.Update()
+	assert.True(t, root.HasNewLayout())
+	assert.True(t, root.GetChild(20).HasNewLayout())
+	assertSpatialQuery(t, index, root, Rect{Left: 0, Top: 500, Width: 100, Height: 25})
+}
+
+func markLayoutSeenRecursive(node *Node) {
+	node.MarkLayoutSeen()
+	for _, child := range node.Children {
+		markLayoutSeenRecursive(child)
+	}
+}
+
+func TestSpatialIndex_update(t *testing.T) {
+	root := newSpatialTestFeed(100)
+	index := NewSpatialIndex(root)
+	rect := Rect{Left: 0, Top: 500, Width: 100, Height: 25}
+
+	// moving items moves their children
+	root.GetChild(10).StyleSetHeight(30)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	index.Update()
+	assertSpatialQuery(t, index, root, rect)
+	assert.Same(t, root.GetChild(48), index.Query(rect)[1])
+
+	removed := root.GetChild(48)
+	root.RemoveChild(removed)
+	root.GetChild(49).StyleSetDisplay(DisplayNone)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	index.Update()
+	assertSpatialQuery(t, index, root, rect)
+	assert.Equal(t, 2*98+1, index.Len())
+
+	root.GetChild(49).StyleSetDisplay(DisplayFlex)
+	root.InsertChild(removed, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	index.Update()
+	assertSpatialQuery(t, index, root, rect)
+	assert.Equal(t, 2*100+1, index.Len())
+}
+
+func TestSpatialIndex_random_changes(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	root := newSpatialTestFeed(200)
+	index := NewSpatialIndex(root)
+
+	for round := 0; round < 50; round++ {
+		for i := 0; i < 5; i++ {
+			child := root.GetChild(rnd.Intn(len(root.Children)))
+			switch rnd.Intn(4) {
+			case 0:
+				child.StyleSetHeight(float32(5 + rnd.Intn(30)))
+			case 1:
+				root.RemoveChild(child)
+				root.InsertChild(child, rnd.Intn(len(root.Children)+1))
+			case 2:
+				child.GetChild(0).StyleSetWidth(float32(1 + rnd.Intn(99)))
+			case 3:
+				if child.Style.Display == DisplayNone {
+					child.StyleSetDisplay(DisplayFlex)
+				} else {
+					child.StyleSetDisplay(DisplayNone)
+				}
+			}
+		}
+		CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+		index.Update()
+
+		rect := Rect{
+			Left:   float32(rnd.Intn(100)),
+			Top:    float32(rnd.Intn(2000)),
+			Width:  float32(rnd.Intn(50)),
+			Height: float32(rnd.Intn(200)),
+		}
+		assertSpatialQuery(t, index, root, rect)
+		assert.Equal(t, len(NodesInRect(root, Rect{Width: 1000, Height: 10000}, false)), index.Len())
+	}
+}
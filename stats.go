@@ -0,0 +1,39 @@
+package flex
+
+// LayoutStats describes the work done by a single CalculateLayoutWithStats call
+type LayoutStats struct {
+	// NodesVisited is the number of times a node was visited, including
+	// visits answered from the cache
+	NodesVisited int
+	// Layouts is the number of times a node was laid out because there was
+	// no usable cached layout
+	Layouts int
+	// Measurements is the number of times a node was measured because there
+	// was no usable cached measurement
+	Measurements int
+	// MeasureFuncCalls is the number of calls to Node.Measure
+	MeasureFuncCalls int
+	// CachedLayoutHits is the number of visits answered from cachedLayout
+	CachedLayoutHits int
+	// CachedMeasurementHits is the number of visits answered from
+	// cachedMeasurements
+	CachedMeasurementHits int
+	// MaxDepth is the deepest recursion level, 1 for the root node
+	MaxDepth int
+	// CacheOverflows is the number of times all cachedMeasurements entries
+	// of a node were used and the ring started over
+	CacheOverflows int
+	// StyleChanges is the number of nodes with style changed without marking
+	// them dirty, counted if Config.DetectStyleChanges is set
+	StyleChanges int
+}
+
+// CacheHits returns the number of visits answered from any cache
+func (stats *LayoutStats) CacheHits() int {
+	return stats.CachedLayoutHits + stats.CachedMeasurementHits
+}
+
+// CacheOverflowed returns true if the measurement cache of any node overflowed
+func (stats *LayoutStats) CacheOverflowed() bool {
+	return stats.CacheOverflows > 0
+}
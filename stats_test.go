@@ -0,0 +1,85 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayout_stats(t *testing.T) {
+	root := NewNode()
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild0, 0)
+
+	stats := CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 4, stats.NodesVisited)
+	assert.Equal(t, 2, stats.Layouts)
+	assert.Equal(t, 2, stats.Measurements)
+	assert.Equal(t, 0, stats.MeasureFuncCalls)
+	assert.Equal(t, 0, stats.CacheHits())
+	assert.Equal(t, 2, stats.MaxDepth)
+	assert.False(t, stats.CacheOverflowed())
+
+	stats = CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 1, stats.NodesVisited)
+	assert.Equal(t, 0, stats.Layouts)
+	assert.Equal(t, 0, stats.Measurements)
+	assert.Equal(t, 1, stats.CachedLayoutHits)
+	assert.Equal(t, 0, stats.CachedMeasurementHits)
+	assert.Equal(t, 1, stats.MaxDepth)
+}
+
+func TestLayout_stats_measure_func_calls(t *testing.T) {
+	measureCount := 0
+
+	root := NewNode()
+	root.StyleSetAlignItems(AlignFlexStart)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNode()
+	rootChild0.SetMeasureFunc(func(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+		measureCount++
+		return Size{Width: 10, Height: 10}
+	})
+	root.InsertChild(rootChild0, 0)
+
+	stats := CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+	assert.True(t, stats.MeasureFuncCalls > 0)
+	assert.Equal(t, measureCount, stats.MeasureFuncCalls)
+	assert.True(t, stats.CachedMeasurementHits+stats.CachedLayoutHits > 0)
+
+	// the measured size still fits, so the measure func is not called again
+	root.StyleSetWidth(90)
+	stats = CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 0, stats.MeasureFuncCalls)
+	assert.True(t, stats.CacheHits() > 0)
+}
+
+func TestLayout_stats_cache_overflow(t *testing.T) {
+	root := NewNode()
+
+	rootChild0 := NewNode()
+	rootChild0.StyleSetFlexDirection(FlexDirectionRow)
+	root.InsertChild(rootChild0, 0)
+
+	rootChild0Child0 := NewNode()
+	rootChild0Child0.StyleSetFlexGrow(1)
+	rootChild0Child0.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+
+	// rootChild0 isn't dirty, so each new width adds measurements to its ring
+	stats := CalculateLayoutWithStats(root, 100, Undefined, DirectionLTR)
+	assert.False(t, stats.CacheOverflowed())
+
+	overflowed := false
+	for i := 1; i <= maxCachedResultCount; i++ {
+		stats = CalculateLayoutWithStats(root, float32(100+i), Undefined, DirectionLTR)
+		overflowed = overflowed || stats.CacheOverflowed()
+	}
+	assert.True(t, overflowed)
+}
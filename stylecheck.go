@@ -0,0 +1,32 @@
+package flex
+
+// nodeDetectStyleChanges marks dirty node and its descendants whose style
+// changed since the previous layout without marking them dirty
+func nodeDetectStyleChanges(node *Node, ctx *layoutContext) {
+	if node.layoutStyle == nil {
+		node.layoutStyle = &Style{}
+	} else if !node.IsDirty && !styleEq(&node.Style, node.layoutStyle) {
+		ctx.stats.StyleChanges++
+		log(node, LogLevelWarn, "Style of node changed without marking it dirty\n")
+		nodeMarkDirtyInternal(node)
+	}
+	nodeCopyStyleForLayout(node.layoutStyle, &node.Style)
+
+	for _, child := range node.Children {
+		// child shared with another tree is checked by its owner
+		if child.Parent != node {
+			continue
+		}
+		nodeDetectStyleChanges(child, ctx)
+	}
+}
+
+// nodeCopyStyleForLayout copies src to dst, including grid tracks, so changing
+// tracks in place is detected too
+func nodeCopyStyleForLayout(dst *Style, src *Style) {
+	columns := append(dst.GridTemplateColumns[:0], src.GridTemplateColumns...)
+	rows := append(dst.GridTemplateRows[:0], src.GridTemplateRows...)
+	*dst = *src
+	dst.GridTemplateColumns = columns
+	dst.GridTemplateRows = rows
+}
@@ -0,0 +1,140 @@
+package text
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Metrics measures text set in a single font
+type Metrics interface {
+	// Advance returns width of s
+	Advance(s string) float32
+	// Ascent returns distance from the top of a line to its baseline
+	Ascent() float32
+	// Descent returns distance from the baseline to the bottom of a line
+	Descent() float32
+	// LineHeight returns recommended distance between baselines of lines
+	LineHeight() float32
+}
+
+// FaceMetrics measures text with a font face
+type FaceMetrics struct {
+	Face font.Face
+}
+
+// NewFaceMetrics creates metrics of face
+func NewFaceMetrics(face font.Face) *FaceMetrics {
+	return &FaceMetrics{Face: face}
+}
+
+func fixedToFloat(v fixed.Int26_6) float32 {
+	return float32(v) / 64
+}
+
+// Advance returns width of s, including kerning
+func (m *FaceMetrics) Advance(s string) float32 {
+	return fixedToFloat(font.MeasureString(m.Face, s))
+}
+
+// Ascent returns ascent of the face
+func (m *FaceMetrics) Ascent() float32 {
+	return fixedToFloat(m.Face.Metrics().Ascent)
+}
+
+// Descent returns descent of the face
+func (m *FaceMetrics) Descent() float32 {
+	return fixedToFloat(m.Face.Metrics().Descent)
+}
+
+// LineHeight returns height of the face
+func (m *FaceMetrics) LineHeight() float32 {
+	return fixedToFloat(m.Face.Metrics().Height)
+}
+
+// CellMetrics measures text displayed in a grid of fixed size cells, like in
+// a terminal. Wide characters take 2 cells, combining marks and control
+// characters don't take any
+type CellMetrics struct {
+	// CellWidth is width of a cell, 1 if 0
+	CellWidth float32
+	// CellHeight is height of a cell, 1 if 0
+	CellHeight float32
+}
+
+func (m CellMetrics) cellWidth() float32 {
+	if m.CellWidth == 0 {
+		return 1
+	}
+	return m.CellWidth
+}
+
+func (m CellMetrics) cellHeight() float32 {
+	if m.CellHeight == 0 {
+		return 1
+	}
+	return m.CellHeight
+}
+
+// Advance returns width of cells taken by s
+func (m CellMetrics) Advance(s string) float32 {
+	cells := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		cells += runeCells(r)
+		s = s[size:]
+	}
+	return float32(cells) * m.cellWidth()
+}
+
+// Ascent returns height of a cell, text sits on the bottom of cells
+func (m CellMetrics) Ascent() float32 {
+	return m.cellHeight()
+}
+
+// Descent returns 0
+func (m CellMetrics) Descent() float32 {
+	return 0
+}
+
+// LineHeight returns height of a cell
+func (m CellMetrics) LineHeight() float32 {
+	return m.cellHeight()
+}
+
+// wideRanges are ranges of East Asian wide and fullwidth characters
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+// runeCells returns number of cells taken by r
+func runeCells(r rune) int {
+	if r < 0x20 || (r >= 0x7F && r < 0xA0) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) {
+		return 0
+	}
+	for _, wide := range wideRanges {
+		if r < wide.lo {
+			break
+		}
+		if r <= wide.hi {
+			return 2
+		}
+	}
+	return 1
+}
@@ -0,0 +1,229 @@
+// Package text measures and wraps text for leaf nodes of flex layout.
+//
+// Text implements flex.MeasureFunc and flex.BaselineFunc:
+//
+//	label := &text.Text{Text: "Hello world", Metrics: text.NewFaceMetrics(face)}
+//	node := flex.New(flex.FlexShrink(1), label.Option())
+//
+// After changing Text call MarkDirty on its node.
+package text
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kjk/flex"
+)
+
+// wrapEpsilon hides float errors, text measured at width w must wrap the
+// same way when laid out at width w
+const wrapEpsilon = 0.001
+
+// Text is a string set in a single font
+type Text struct {
+	Text    string
+	Metrics Metrics
+	// LineHeight is distance between baselines of lines. If it's 0,
+	// LineHeight of Metrics is used
+	LineHeight float32
+	// MaxLines limits the number of lines, 0 means no limit
+	MaxLines int
+}
+
+// Line is a line of wrapped text
+type Line struct {
+	Text  string
+	Width float32
+	// Baseline is distance of the baseline of the line from the top of the
+	// text
+	Baseline float32
+}
+
+// Block is text wrapped into lines
+type Block struct {
+	Lines  []Line
+	Width  float32
+	Height float32
+	// Baseline is distance of the baseline of the first line from the top
+	// of the text
+	Baseline float32
+	// Truncated is true if lines were dropped because of MaxLines
+	Truncated bool
+}
+
+func (t *Text) lineHeight() float32 {
+	if t.LineHeight > 0 {
+		return t.LineHeight
+	}
+	return t.Metrics.LineHeight()
+}
+
+// firstBaseline returns distance of the baseline of a line from its top.
+// Extra space of line height is split evenly above and below the text
+func (t *Text) firstBaseline() float32 {
+	ascent := t.Metrics.Ascent()
+	return (t.lineHeight()-ascent-t.Metrics.Descent())/2 + ascent
+}
+
+// Layout wraps text into lines that are at most maxWidth wide. Lines break
+// at spaces and at '\n', words longer than maxWidth are broken between
+// characters. Runs of spaces are collapsed into one. If maxWidth is
+// flex.Undefined, text wraps only at '\n'
+func (t *Text) Layout(maxWidth float32) *Block {
+	w := wrapper{
+		metrics:  t.Metrics,
+		maxWidth: maxWidth,
+		maxLines: t.MaxLines,
+		space:    t.Metrics.Advance(" "),
+	}
+	if flex.FloatIsUndefined(maxWidth) {
+		w.maxWidth = float32(1e30)
+	}
+	if t.Text != "" {
+		w.wrap(t.Text)
+	}
+
+	block := &Block{Lines: w.lines, Truncated: w.truncated}
+	lineHeight := t.lineHeight()
+	baseline := t.firstBaseline()
+	for i := range block.Lines {
+		line := &block.Lines[i]
+		line.Baseline = float32(i)*lineHeight + baseline
+		if line.Width > block.Width {
+			block.Width = line.Width
+		}
+	}
+	block.Height = float32(len(block.Lines)) * lineHeight
+	if len(block.Lines) > 0 {
+		block.Baseline = baseline
+	}
+	return block
+}
+
+// Measure measures text, it's a flex.MeasureFunc
+func (t *Text) Measure(node *flex.Node, width float32, widthMode flex.MeasureMode, height float32, heightMode flex.MeasureMode) flex.Size {
+	maxWidth := width
+	if widthMode == flex.MeasureModeUndefined {
+		maxWidth = flex.Undefined
+	}
+	block := t.Layout(maxWidth)
+	size := flex.Size{Width: block.Width, Height: block.Height}
+	switch widthMode {
+	case flex.MeasureModeExactly:
+		size.Width = width
+	case flex.MeasureModeAtMost:
+		if size.Width > width {
+			size.Width = width
+		}
+	}
+	switch heightMode {
+	case flex.MeasureModeExactly:
+		size.Height = height
+	case flex.MeasureModeAtMost:
+		if size.Height > height {
+			size.Height = height
+		}
+	}
+	return size
+}
+
+// Baseline returns baseline of the first line of text in node, it's a
+// flex.BaselineFunc
+func (t *Text) Baseline(node *flex.Node, width float32, height float32) float32 {
+	if t.Text == "" {
+		return height
+	}
+	return node.LayoutGetPadding(flex.EdgeTop) + node.LayoutGetBorder(flex.EdgeTop) + t.firstBaseline()
+}
+
+// Option sets Measure and Baseline of a node to t
+func (t *Text) Option() flex.Option {
+	return flex.OptionFunc(func(node *flex.Node) {
+		node.SetMeasureFunc(t.Measure)
+		node.Baseline = t.Baseline
+	})
+}
+
+// wrapper collects lines of wrapped text
+type wrapper struct {
+	metrics   Metrics
+	maxWidth  float32
+	maxLines  int
+	space     float32
+	lines     []Line
+	truncated bool
+
+	words []string
+	width float32
+}
+
+func (w *wrapper) full() bool {
+	return w.maxLines > 0 && len(w.lines) >= w.maxLines
+}
+
+func (w *wrapper) flush() {
+	if w.full() {
+		w.truncated = true
+	} else {
+		w.lines = append(w.lines, Line{Text: strings.Join(w.words, " "), Width: w.width})
+	}
+	w.words = w.words[:0]
+	w.width = 0
+}
+
+func (w *wrapper) wrap(s string) {
+	for _, paragraph := range strings.Split(s, "\n") {
+		for _, word := range strings.Fields(paragraph) {
+			w.addWord(word)
+			if w.truncated {
+				return
+			}
+		}
+		w.flush()
+		if w.truncated {
+			return
+		}
+	}
+}
+
+func (w *wrapper) addWord(word string) {
+	width := w.metrics.Advance(word)
+	if len(w.words) > 0 {
+		if w.width+w.space+width <= w.maxWidth+wrapEpsilon {
+			w.words = append(w.words, word)
+			w.width += w.space + width
+			return
+		}
+		w.flush()
+	}
+	// break words that don't fit on a line
+	for width > w.maxWidth+wrapEpsilon && !w.truncated {
+		n, partWidth := w.fitting(word)
+		w.words = append(w.words, word[:n])
+		w.width = partWidth
+		w.flush()
+		word = word[n:]
+		width = w.metrics.Advance(word)
+	}
+	if word != "" {
+		w.words = append(w.words, word)
+		w.width = width
+	}
+}
+
+// fitting returns the length and width of the longest prefix of word that
+// fits on a line, at least one character
+func (w *wrapper) fitting(word string) (int, float32) {
+	_, n := utf8.DecodeRuneInString(word)
+	width := w.metrics.Advance(word[:n])
+	for n < len(word) {
+		_, size := utf8.DecodeRuneInString(word[n:])
+		next := w.metrics.Advance(word[:n+size])
+		if next > w.maxWidth+wrapEpsilon {
+			break
+		}
+		n += size
+		width = next
+	}
+	return n, width
+}
@@ -0,0 +1,102 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/kjk/flex"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/font/basicfont"
+)
+
+func lineTexts(block *Block) []string {
+	var lines []string
+	for _, line := range block.Lines {
+		lines = append(lines, line.Text)
+	}
+	return lines
+}
+
+func TestText_wraps_words(t *testing.T) {
+	txt := &Text{Text: "the quick  brown fox\njumps over", Metrics: CellMetrics{}}
+
+	block := txt.Layout(flex.Undefined)
+	assert.Equal(t, []string{"the quick brown fox", "jumps over"}, lineTexts(block))
+	assert.Equal(t, float32(19), block.Width)
+	assert.Equal(t, float32(2), block.Height)
+
+	block = txt.Layout(10)
+	assert.Equal(t, []string{"the quick", "brown fox", "jumps over"}, lineTexts(block))
+	assert.Equal(t, float32(10), block.Width)
+	assert.Equal(t, float32(3), block.Height)
+	assert.Equal(t, float32(1), block.Baseline)
+	assert.Equal(t, float32(3), block.Lines[2].Baseline)
+
+	block = txt.Layout(4)
+	assert.Equal(t, []string{"the", "quic", "k", "brow", "n", "fox", "jump", "s", "over"}, lineTexts(block))
+}
+
+func TestText_max_lines_and_line_height(t *testing.T) {
+	txt := &Text{Text: "one two three four", Metrics: CellMetrics{CellWidth: 2, CellHeight: 4}, LineHeight: 6, MaxLines: 2}
+
+	block := txt.Layout(18)
+	assert.Equal(t, []string{"one two", "three"}, lineTexts(block))
+	assert.True(t, block.Truncated)
+	assert.Equal(t, float32(14), block.Width)
+	assert.Equal(t, float32(12), block.Height)
+	// 1 point of extra line height is above the text
+	assert.Equal(t, float32(5), block.Baseline)
+	assert.Equal(t, float32(11), block.Lines[1].Baseline)
+
+	txt.MaxLines = 0
+	block = txt.Layout(18)
+	assert.False(t, block.Truncated)
+	assert.Equal(t, 3, len(block.Lines))
+}
+
+func TestText_cell_widths(t *testing.T) {
+	m := CellMetrics{}
+	assert.Equal(t, float32(5), m.Advance("hello"))
+	assert.Equal(t, float32(4), m.Advance("日本"))
+	assert.Equal(t, float32(1), m.Advance("é"))
+}
+
+func TestText_measure_modes(t *testing.T) {
+	txt := &Text{Text: "aaa bbb ccc", Metrics: CellMetrics{}}
+
+	assert.Equal(t, flex.Size{Width: 11, Height: 1}, txt.Measure(nil, flex.Undefined, flex.MeasureModeUndefined, flex.Undefined, flex.MeasureModeUndefined))
+	assert.Equal(t, flex.Size{Width: 7, Height: 2}, txt.Measure(nil, 8, flex.MeasureModeAtMost, flex.Undefined, flex.MeasureModeUndefined))
+	assert.Equal(t, flex.Size{Width: 8, Height: 2}, txt.Measure(nil, 8, flex.MeasureModeExactly, flex.Undefined, flex.MeasureModeUndefined))
+	assert.Equal(t, flex.Size{Width: 3, Height: 2}, txt.Measure(nil, 3, flex.MeasureModeAtMost, 2, flex.MeasureModeAtMost))
+	assert.Equal(t, flex.Size{Width: 3, Height: 5}, txt.Measure(nil, 3, flex.MeasureModeAtMost, 5, flex.MeasureModeExactly))
+}
+
+func TestText_face_metrics(t *testing.T) {
+	m := NewFaceMetrics(basicfont.Face7x13)
+	assert.Equal(t, float32(35), m.Advance("hello"))
+	assert.Equal(t, float32(11), m.Ascent())
+	assert.Equal(t, float32(2), m.Descent())
+	assert.Equal(t, float32(13), m.LineHeight())
+
+	txt := &Text{Text: "hello world", Metrics: m}
+	block := txt.Layout(50)
+	assert.Equal(t, []string{"hello", "world"}, lineTexts(block))
+	assert.Equal(t, float32(26), block.Height)
+	assert.Equal(t, float32(11), block.Baseline)
+}
+
+func TestText_in_layout(t *testing.T) {
+	label := &Text{Text: "aaa bbb", Metrics: CellMetrics{}, LineHeight: 3}
+	text := flex.New(flex.FlexShrink(1), flex.Padding(flex.EdgeTop, 1), label.Option())
+	icon := flex.New(flex.Width(2), flex.Height(2))
+	root := flex.New(flex.Row, flex.AlignItems(flex.AlignBaseline), flex.Width(6), flex.Height(10),
+		flex.Children(icon, text),
+	)
+	flex.CalculateLayout(root, flex.Undefined, flex.Undefined, flex.DirectionLTR)
+
+	// "aaa bbb" doesn't fit into 4 cells left by the icon
+	assert.Equal(t, float32(3), text.LayoutGetWidth())
+	assert.Equal(t, float32(7), text.LayoutGetHeight())
+	// baseline of the text is 1 + 2, baseline of the icon is its bottom
+	assert.Equal(t, float32(0), text.LayoutGetTop())
+	assert.Equal(t, float32(1), icon.LayoutGetTop())
+}
@@ -0,0 +1,168 @@
+package flex
+
+// LayoutEventType is type of LayoutEvent
+type LayoutEventType int
+
+const (
+	// LayoutEventEnter is sent before a node is laid out or measured because
+	// there was no usable cached result
+	LayoutEventEnter LayoutEventType = iota
+	// LayoutEventExit is sent after a node was laid out or measured
+	LayoutEventExit
+	// LayoutEventCacheHit is sent when a cached result is used instead of
+	// laying out or measuring a node
+	LayoutEventCacheHit
+	// LayoutEventCacheOverflow is sent when all measurement cache entries of
+	// a node are used and the cache starts over
+	LayoutEventCacheOverflow
+	// LayoutEventDone is sent for the root node after CalculateLayout
+	// laid out the tree
+	LayoutEventDone
+)
+
+// LayoutEventTypeToString returns string version of LayoutEventType enum
+func LayoutEventTypeToString(value LayoutEventType) string {
+	switch value {
+	case LayoutEventEnter:
+		return "enter"
+	case LayoutEventExit:
+		return "exit"
+	case LayoutEventCacheHit:
+		return "cache-hit"
+	case LayoutEventCacheOverflow:
+		return "cache-overflow"
+	case LayoutEventDone:
+		return "done"
+	}
+	return "unknown"
+}
+
+// LayoutEvent describes a visit of a node during CalculateLayout
+type LayoutEvent struct {
+	Type LayoutEventType
+	Node *Node
+	// Depth is recursion depth, 1 for the root node
+	Depth int
+	// Reason is why the node was visited: "initial", "measure", "flex",
+	// "stretch", "multiline-stretch", "abs-measure", "abs-layout",
+	// "grid-measure", "grid", "block-measure" or "block"
+	Reason string
+	// PerformLayout is false if only the size of the node was requested
+	PerformLayout bool
+	// NeedToVisit is true if the node was dirty or its direction changed,
+	// which invalidates its cached results
+	NeedToVisit bool
+
+	AvailableWidth    float32
+	AvailableHeight   float32
+	WidthMeasureMode  MeasureMode
+	HeightMeasureMode MeasureMode
+
+	// MeasuredWidth and MeasuredHeight are Undefined for LayoutEventEnter
+	// and LayoutEventCacheOverflow
+	MeasuredWidth  float32
+	MeasuredHeight float32
+}
+
+// LayoutTracer receives events while a tree is laid out. It's set
+// on Config.Tracer. Events of a single CalculateLayout call come from the
+// goroutine that made the call, but trees that share a config can be laid
+// out concurrently and then TraceLayout is called from several goroutines
+type LayoutTracer interface {
+	TraceLayout(event LayoutEvent)
+}
+
+// LayoutTracerFunc is a function that implements LayoutTracer
+type LayoutTracerFunc func(event LayoutEvent)
+
+// TraceLayout calls f(event)
+func (f LayoutTracerFunc) TraceLayout(event LayoutEvent) {
+	f(event)
+}
+
+// LogTracer is a LayoutTracer that logs with Config.Logger, in the same
+// format as C version of Yoga. Layout changes are logged at LogLevelVerbose
+// and the tree at LogLevelDebug
+type LogTracer struct {
+	// PrintChanges logs nodes that were laid out or measured
+	PrintChanges bool
+	// PrintSkips, together with PrintChanges, also logs nodes that used
+	// cached results
+	PrintSkips bool
+	// PrintTree logs the whole tree with NodePrint after layout
+	PrintTree bool
+}
+
+// TraceLayout logs event
+func (tracer *LogTracer) TraceLayout(event LayoutEvent) {
+	node := event.Node
+	switch event.Type {
+	case LayoutEventCacheHit:
+		if !tracer.PrintChanges || !tracer.PrintSkips {
+			return
+		}
+		tracer.log(node, "%s%d.{[skipped] wm: %s, hm: %s, aw: %f ah: %f => d: (%f, %f) %s\n",
+			spacer(event.Depth),
+			event.Depth,
+			measureModeName(event.WidthMeasureMode, event.PerformLayout),
+			measureModeName(event.HeightMeasureMode, event.PerformLayout),
+			event.AvailableWidth,
+			event.AvailableHeight,
+			event.MeasuredWidth,
+			event.MeasuredHeight,
+			event.Reason)
+	case LayoutEventEnter:
+		if !tracer.PrintChanges {
+			return
+		}
+		s := ""
+		if event.NeedToVisit {
+			s = "*"
+		}
+		tracer.log(node, "%s%d.{%swm: %s, hm: %s, aw: %f ah: %f %s\n",
+			spacer(event.Depth),
+			event.Depth,
+			s,
+			measureModeName(event.WidthMeasureMode, event.PerformLayout),
+			measureModeName(event.HeightMeasureMode, event.PerformLayout),
+			event.AvailableWidth,
+			event.AvailableHeight,
+			event.Reason)
+	case LayoutEventExit:
+		if !tracer.PrintChanges {
+			return
+		}
+		s := ""
+		if event.NeedToVisit {
+			s = "*"
+		}
+		tracer.log(node, "%s%d.}%swm: %s, hm: %s, d: (%f, %f) %s\n",
+			spacer(event.Depth),
+			event.Depth,
+			s,
+			measureModeName(event.WidthMeasureMode, event.PerformLayout),
+			measureModeName(event.HeightMeasureMode, event.PerformLayout),
+			event.MeasuredWidth,
+			event.MeasuredHeight,
+			event.Reason)
+	case LayoutEventCacheOverflow:
+		if !tracer.PrintChanges {
+			return
+		}
+		log(node, LogLevelVerbose, "Out of cache entries!\n")
+	case LayoutEventDone:
+		if tracer.PrintTree {
+			NodePrint(node, PrintOptionsLayout|PrintOptionsChildren|PrintOptionsStyle)
+		}
+	}
+}
+
+// log logs a single line of an event with one call of Config.Logger, so that
+// loggers that emit a record per call, like NewSlogLogger, get the whole
+// line. Output of node.Print goes before the line
+func (tracer *LogTracer) log(node *Node, format string, args ...interface{}) {
+	if node.Print != nil {
+		node.Print(node)
+	}
+	log(node, LogLevelVerbose, format, args...)
+}
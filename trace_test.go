@@ -0,0 +1,180 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTracer struct {
+	events []LayoutEvent
+}
+
+func (tracer *testTracer) TraceLayout(event LayoutEvent) {
+	tracer.events = append(tracer.events, event)
+}
+
+func (tracer *testTracer) eventsFor(node *Node) []LayoutEvent {
+	var res []LayoutEvent
+	for _, e := range tracer.events {
+		if e.Node == node {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func TestTrace_layout_events(t *testing.T) {
+	tracer := &testTracer{}
+	config := NewConfig()
+	config.Tracer = tracer
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	events := tracer.events
+	assert.True(t, len(events) >= 4)
+
+	first := events[0]
+	assert.Equal(t, LayoutEventEnter, first.Type)
+	assert.Equal(t, root, first.Node)
+	assert.Equal(t, 1, first.Depth)
+	assert.Equal(t, "initial", first.Reason)
+	assert.True(t, first.PerformLayout)
+	assert.True(t, first.NeedToVisit)
+	assertFloatEqual(t, 100, first.AvailableWidth)
+	assert.Equal(t, MeasureModeExactly, first.WidthMeasureMode)
+	assert.True(t, FloatIsUndefined(first.MeasuredWidth))
+
+	exit := events[len(events)-2]
+	assert.Equal(t, LayoutEventExit, exit.Type)
+	assert.Equal(t, root, exit.Node)
+	assertFloatEqual(t, 100, exit.MeasuredWidth)
+	assertFloatEqual(t, 100, exit.MeasuredHeight)
+
+	done := events[len(events)-1]
+	assert.Equal(t, LayoutEventDone, done.Type)
+	assert.Equal(t, root, done.Node)
+
+	childEvents := tracer.eventsFor(rootChild0)
+	assert.Equal(t, 6, len(childEvents))
+	reasons := []string{"measure", "measure", "flex", "flex", "stretch", "stretch"}
+	for i, e := range childEvents {
+		assert.Equal(t, reasons[i], e.Reason)
+		assert.Equal(t, 2, e.Depth)
+		if i%2 == 0 {
+			assert.Equal(t, LayoutEventEnter, e.Type)
+		} else {
+			assert.Equal(t, LayoutEventExit, e.Type)
+		}
+	}
+	assert.False(t, childEvents[2].PerformLayout)
+	assert.True(t, childEvents[4].PerformLayout)
+	assertFloatEqual(t, 100, childEvents[5].MeasuredWidth)
+	assertFloatEqual(t, 100, childEvents[5].MeasuredHeight)
+}
+
+func TestTrace_cache_hit(t *testing.T) {
+	tracer := &testTracer{}
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexGrow(1)
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	config.Tracer = tracer
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.Equal(t, 1, len(tracer.events))
+	hit := tracer.events[0]
+	assert.Equal(t, LayoutEventCacheHit, hit.Type)
+	assert.Equal(t, root, hit.Node)
+	assert.False(t, hit.NeedToVisit)
+	assertFloatEqual(t, 100, hit.MeasuredWidth)
+	assertFloatEqual(t, 100, hit.MeasuredHeight)
+}
+
+func TestTrace_measure_reason(t *testing.T) {
+	tracer := &testTracer{}
+	config := NewConfig()
+	config.Tracer = tracer
+
+	root := NewNodeWithConfig(config)
+	root.StyleSetAlignItems(AlignFlexStart)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(100)
+
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.SetMeasureFunc(func(node *Node, width float32, widthMode MeasureMode, height float32, heightMode MeasureMode) Size {
+		return Size{Width: 10, Height: 10}
+	})
+	root.InsertChild(rootChild0, 0)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	childEvents := tracer.eventsFor(rootChild0)
+	assert.NotEmpty(t, childEvents)
+	assert.Equal(t, LayoutEventEnter, childEvents[0].Type)
+	assert.Equal(t, "measure", childEvents[0].Reason)
+	assert.False(t, childEvents[0].PerformLayout)
+}
+
+func TestTrace_cache_overflow(t *testing.T) {
+	tracer := &testTracer{}
+	config := NewConfig()
+
+	root := NewNodeWithConfig(config)
+	rootChild0 := NewNodeWithConfig(config)
+	rootChild0.StyleSetFlexDirection(FlexDirectionRow)
+	root.InsertChild(rootChild0, 0)
+	rootChild0Child0 := NewNodeWithConfig(config)
+	rootChild0Child0.StyleSetFlexGrow(1)
+	rootChild0Child0.StyleSetHeight(10)
+	rootChild0.InsertChild(rootChild0Child0, 0)
+	CalculateLayout(root, 100, Undefined, DirectionLTR)
+
+	config.Tracer = tracer
+	for i := 1; i <= maxCachedResultCount; i++ {
+		CalculateLayout(root, float32(100+i), Undefined, DirectionLTR)
+	}
+
+	overflows := 0
+	for _, e := range tracer.events {
+		if e.Type == LayoutEventCacheOverflow {
+			overflows++
+			assert.True(t, FloatIsUndefined(e.MeasuredWidth))
+			assert.True(t, FloatIsUndefined(e.MeasuredHeight))
+		}
+	}
+	assert.True(t, overflows > 0)
+}
+
+func TestTrace_func(t *testing.T) {
+	n := 0
+	config := NewConfig()
+	config.Tracer = LayoutTracerFunc(func(event LayoutEvent) {
+		n++
+	})
+	root := NewNodeWithConfig(config)
+	CalculateLayout(root, 100, 100, DirectionLTR)
+	assert.Equal(t, 3, n)
+}
+
+func TestLayout_event_type_to_string(t *testing.T) {
+	assert.Equal(t, "enter", LayoutEventTypeToString(LayoutEventEnter))
+	assert.Equal(t, "exit", LayoutEventTypeToString(LayoutEventExit))
+	assert.Equal(t, "cache-hit", LayoutEventTypeToString(LayoutEventCacheHit))
+	assert.Equal(t, "cache-overflow", LayoutEventTypeToString(LayoutEventCacheOverflow))
+	assert.Equal(t, "done", LayoutEventTypeToString(LayoutEventDone))
+	assert.Equal(t, "unknown", LayoutEventTypeToString(LayoutEventType(-1)))
+}
@@ -0,0 +1,368 @@
+package flex
+
+// VirtualList creates children of a flex container on demand so that long
+// lists don't need a node per item. Only items that intersect the window,
+// extended by Overscan on both sides, are created and laid out. Space of
+// other items is taken by two spacer children with sizes estimated from
+// items that were measured before.
+//
+// Items are laid out along the main axis of the container. Gap and wrapping
+// aren't supported
+type VirtualList struct {
+	// EstimatedItemSize is the size along main axis, including margins, of
+	// items that weren't measured yet. If it's 0, average size of measured
+	// items is used
+	EstimatedItemSize float32
+	// Overscan is the distance before and after the window in which items
+	// are created too
+	Overscan float32
+
+	node    *Node
+	count   int
+	newItem func(index int) *Node
+	// sizes are sizes of items along main axis including margins, Undefined
+	// if item wasn't measured yet
+	sizes []float32
+	items map[int]*Node
+
+	leadingSpacer  *Node
+	trailingSpacer *Node
+
+	windowOffset float32
+	windowSize   float32
+}
+
+// defaultVirtualListItemSize is used to estimate size of items before any
+// item was measured and if EstimatedItemSize isn't set
+const defaultVirtualListItemSize = 20
+
+// NewVirtualList creates a list of count items. newItem is called to create
+// item at index when it gets into the window
+func NewVirtualList(count int, newItem func(index int) *Node) *VirtualList {
+	list := &VirtualList{
+		newItem:    newItem,
+		items:      make(map[int]*Node),
+		windowSize: Undefined,
+	}
+	list.resize(count)
+	return list
+}
+
+// SetVirtualList makes list create children of node. A nil list removes
+// the list of node and its children
+func (node *Node) SetVirtualList(list *VirtualList) {
+	if err := node.TrySetVirtualList(list); err != nil {
+		assertFailed(node.Config, node, err.Error())
+	}
+}
+
+// TrySetVirtualList makes list create children of node, returns an error
+// instead of panicking if node has children or a measure function or if
+// list is set on another node. A nil list removes the list of node and its
+// children
+func (node *Node) TrySetVirtualList(list *VirtualList) error {
+	if list == node.virtualList {
+		return nil
+	}
+	if list != nil && list.node != nil {
+		return ErrVirtualListAttached
+	}
+	if node.virtualList == nil && (len(node.Children) > 0 || node.Measure != nil) {
+		return ErrVirtualListNodeHasChildren
+	}
+	if node.virtualList != nil {
+		node.virtualList.detach()
+	}
+	if list != nil {
+		list.node = node
+		list.leadingSpacer = newVirtualListSpacer(node.Config)
+		list.trailingSpacer = newVirtualListSpacer(node.Config)
+		node.virtualList = list
+	}
+	nodeMarkDirtyInternal(node)
+	return nil
+}
+
+func newVirtualListSpacer(config *Config) *Node {
+	spacer := NewNodeWithConfig(config)
+	spacer.Style.FlexShrink = 0
+	return spacer
+}
+
+// detach removes list and the children it created from its node. Sizes of
+// measured items are kept
+func (list *VirtualList) detach() {
+	node := list.node
+	for index := range list.items {
+		list.dropItem(index)
+	}
+	for _, spacer := range []*Node{list.leadingSpacer, list.trailingSpacer} {
+		if spacer.Parent == node {
+			spacer.Parent = nil
+		}
+	}
+	node.Children = nil
+	node.virtualList = nil
+	list.node = nil
+}
+
+// Count returns number of items
+func (list *VirtualList) Count() int {
+	return list.count
+}
+
+// SetCount changes number of items. Items at index count and above are
+// dropped
+func (list *VirtualList) SetCount(count int) {
+	if count == list.count {
+		return
+	}
+	list.resize(count)
+	for index := range list.items {
+		if index >= count {
+			list.dropItem(index)
+		}
+	}
+	list.markDirty()
+}
+
+// SetWindow sets the visible part of the list. offset is the distance from
+// the start of the first item along main axis. If size is Undefined, which is
+// the default, the inner size of the list is used if it has a fixed size,
+// otherwise the inner size of its nearest ancestor that has a fixed size or
+// clips its content. Without such ancestor only items in Overscan are
+// created
+func (list *VirtualList) SetWindow(offset float32, size float32) {
+	if list.windowOffset == offset && FloatsEqual(list.windowSize, size) {
+		return
+	}
+	list.windowOffset = offset
+	list.windowSize = size
+	list.markDirty()
+}
+
+// Invalidate drops item at index and forgets its size. The item is created
+// again if it's in the window
+func (list *VirtualList) Invalidate(index int) {
+	list.dropItem(index)
+	list.sizes[index] = Undefined
+	list.markDirty()
+}
+
+// Item returns node of item at index, nil if the item isn't in the window
+func (list *VirtualList) Item(index int) *Node {
+	return list.items[index]
+}
+
+// ItemOffset returns the distance of item at index from the start of the
+// first item along main axis. Sizes of items that weren't measured yet are
+// estimated
+func (list *VirtualList) ItemOffset(index int) float32 {
+	list.updateSizes()
+	estimate := list.estimatedSize()
+	var offset float32
+	for i := 0; i < index && i < list.count; i++ {
+		offset += list.sizeOf(i, estimate)
+	}
+	return offset
+}
+
+func (list *VirtualList) resize(count int) {
+	if count < len(list.sizes) {
+		list.sizes = list.sizes[:count]
+	}
+	for len(list.sizes) < count {
+		list.sizes = append(list.sizes, Undefined)
+	}
+	list.count = count
+}
+
+func (list *VirtualList) markDirty() {
+	if list.node != nil {
+		nodeMarkDirtyInternal(list.node)
+	}
+}
+
+func (list *VirtualList) dropItem(index int) {
+	if item := list.items[index]; item != nil {
+		if item.Parent == list.node {
+			item.Parent = nil
+		}
+		delete(list.items, index)
+	}
+}
+
+func (list *VirtualList) isMainAxisRow() bool {
+	return list.node != nil && flexDirectionIsRow(list.node.Style.FlexDirection)
+}
+
+// updateSizes remembers sizes of items laid out by the previous layout
+func (list *VirtualList) updateSizes() {
+	isMainAxisRow := list.isMainAxisRow()
+	for index, item := range list.items {
+		if item.IsDirty {
+			continue
+		}
+		var size float32
+		if isMainAxisRow {
+			size = item.Layout.Dimensions[DimensionWidth] + item.Layout.Margin[EdgeStart] + item.Layout.Margin[EdgeEnd]
+		} else {
+			size = item.Layout.Dimensions[DimensionHeight] + item.Layout.Margin[EdgeTop] + item.Layout.Margin[EdgeBottom]
+		}
+		if !FloatIsUndefined(size) {
+			list.sizes[index] = size
+		}
+	}
+}
+
+// estimatedSize returns size of items that weren't measured yet
+func (list *VirtualList) estimatedSize() float32 {
+	if list.EstimatedItemSize > 0 {
+		return list.EstimatedItemSize
+	}
+	var total float32
+	n := 0
+	for _, size := range list.sizes {
+		if !FloatIsUndefined(size) {
+			total += size
+			n++
+		}
+	}
+	if n == 0 || total <= 0 {
+		return defaultVirtualListItemSize
+	}
+	return total / float32(n)
+}
+
+func (list *VirtualList) sizeOf(index int, estimate float32) float32 {
+	if size := list.sizes[index]; !FloatIsUndefined(size) {
+		return size
+	}
+	return estimate
+}
+
+// nodeLayoutPaddingAndBorderForAxis returns padding and border of node along
+// axis, computed by the layout of node that's in progress
+func nodeLayoutPaddingAndBorderForAxis(node *Node, isRow bool) float32 {
+	if isRow {
+		return node.Layout.Padding[EdgeStart] + node.Layout.Padding[EdgeEnd] +
+			node.Layout.Border[EdgeStart] + node.Layout.Border[EdgeEnd]
+	}
+	return node.Layout.Padding[EdgeTop] + node.Layout.Padding[EdgeBottom] +
+		node.Layout.Border[EdgeTop] + node.Layout.Border[EdgeBottom]
+}
+
+// viewportSize returns the size of the window if it wasn't set with
+// SetWindow. Size of the list and of its ancestors without a fixed size
+// depends on the items, so it's the inner size of the list if its size is
+// set, otherwise the inner size of the nearest ancestor that has a fixed size
+// or clips its content and was laid out before. It's Undefined if there's
+// no such node
+func (list *VirtualList) viewportSize(parentWidth float32, parentHeight float32) float32 {
+	node := list.node
+	isMainAxisRow := list.isMainAxisRow()
+	dimension := DimensionHeight
+	parentSize := parentHeight
+	if isMainAxisRow {
+		dimension = DimensionWidth
+		parentSize = parentWidth
+	}
+	if size := resolveValue(&node.Style.Dimensions[dimension], parentSize); !FloatIsUndefined(size) {
+		return size - nodeLayoutPaddingAndBorderForAxis(node, isMainAxisRow)
+	}
+	for ancestor := node.Parent; ancestor != nil; ancestor = ancestor.Parent {
+		size := Undefined
+		if value := ancestor.Style.Dimensions[dimension]; value.Unit == UnitPoint {
+			size = value.Value
+		} else if nodeIsClipping(ancestor) {
+			size = ancestor.Layout.Dimensions[dimension]
+		}
+		if !FloatIsUndefined(size) {
+			return size - nodeLayoutPaddingAndBorderForAxis(ancestor, isMainAxisRow)
+		}
+	}
+	return Undefined
+}
+
+// materialize creates items in the window and replaces children of the node
+// with them and the spacers. It's called by layout before the children are
+// laid out
+func (list *VirtualList) materialize(parentWidth float32, parentHeight float32) {
+	node := list.node
+	list.updateSizes()
+
+	mainAxis := FlexDirectionColumn
+	if list.isMainAxisRow() {
+		mainAxis = FlexDirectionRow
+	}
+	windowSize := list.windowSize
+	if FloatIsUndefined(windowSize) {
+		windowSize = list.viewportSize(parentWidth, parentHeight)
+		if FloatIsUndefined(windowSize) {
+			windowSize = 0
+		}
+	}
+	windowStart := list.windowOffset - list.Overscan
+	windowEnd := list.windowOffset + windowSize + list.Overscan
+
+	// offsets of items only grow, so items before the window, in it and
+	// after it follow each other
+	estimate := list.estimatedSize()
+	first, last := 0, 0
+	var leadingSize, trailingSize, offset float32
+	for i := 0; i < list.count; i++ {
+		size := list.sizeOf(i, estimate)
+		if offset+size <= windowStart {
+			leadingSize += size
+			first++
+			last++
+		} else if offset < windowEnd {
+			last++
+		} else {
+			trailingSize += size
+		}
+		offset += size
+	}
+
+	for index := range list.items {
+		if index < first || index >= last {
+			list.dropItem(index)
+		}
+	}
+	children := make([]*Node, 0, last-first+2)
+	children = append(children, list.leadingSpacer)
+	for i := first; i < last; i++ {
+		item := list.items[i]
+		if item == nil {
+			item = list.newItem(i)
+			list.items[i] = item
+		}
+		item.Parent = node
+		children = append(children, item)
+	}
+	children = append(children, list.trailingSpacer)
+	node.Children = children
+
+	list.setSpacerSize(list.leadingSpacer, dim[mainAxis], leadingSize)
+	list.setSpacerSize(list.trailingSpacer, dim[mainAxis], trailingSize)
+}
+
+func (list *VirtualList) setSpacerSize(spacer *Node, dimension Dimension, size float32) {
+	spacer.Parent = list.node
+	value := Value{Value: size, Unit: UnitPoint}
+	if spacer.Style.Dimensions[dimension] == value {
+		return
+	}
+	spacer.Style.Dimensions[DimensionWidth] = ValueAuto
+	spacer.Style.Dimensions[DimensionHeight] = ValueAuto
+	spacer.Style.Dimensions[dimension] = value
+	if spacer.layoutStyle != nil {
+		// the size is changed by layout, not by the user
+		nodeCopyStyleForLayout(spacer.layoutStyle, &spacer.Style)
+	}
+	spacer.IsDirty = true
+	spacer.Layout.computedFlexBasis = Undefined
+	// layout of the list can run more than once in a single layout pass and
+	// the spacer must not be taken from the cache of the previous run
+	spacer.Layout.generationCount = 0
+}
@@ -0,0 +1,216 @@
+package flex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newVirtualListTestRoot(count int, itemHeight func(index int) float32) (*Node, *VirtualList, *int) {
+	created := 0
+	list := NewVirtualList(count, func(index int) *Node {
+		created++
+		item := NewNode()
+		item.StyleSetHeight(itemHeight(index))
+		item.Context = index
+		return item
+	})
+	root := NewNode()
+	root.StyleSetOverflow(OverflowScroll)
+	root.StyleSetWidth(100)
+	root.StyleSetHeight(200)
+	root.SetVirtualList(list)
+	return root, list, &created
+}
+
+func TestVirtualList_materializes_window(t *testing.T) {
+	root, list, created := newVirtualListTestRoot(100000, func(int) float32 { return 20 })
+	list.Overscan = 40
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// 10 items in the window and 2 in overscan after it
+	assert.Equal(t, 12, *created)
+	assert.Equal(t, 14, len(root.Children))
+	assert.Same(t, root, list.Item(0).Parent)
+	assertLayout(t, list.Item(0), 0, 0, 100, 20)
+	assertLayout(t, list.Item(11), 0, 220, 100, 20)
+	assert.Nil(t, list.Item(12))
+	assertFloatEqual(t, 2000000, root.LayoutGetContentHeight())
+
+	list.SetWindow(10000, Undefined)
+	assert.True(t, root.IsDirty)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	assert.Nil(t, list.Item(0))
+	assert.Equal(t, 498, list.Item(498).Context)
+	assertLayout(t, list.Item(498), 0, 9960, 100, 20)
+	assertLayout(t, list.Item(511), 0, 10220, 100, 20)
+	assert.Nil(t, list.Item(497))
+	assert.Nil(t, list.Item(512))
+	assert.Equal(t, 16, len(root.Children))
+	assertFloatEqual(t, 2000000, root.LayoutGetContentHeight())
+	assertFloatEqual(t, 9960, list.ItemOffset(498))
+}
+
+func TestVirtualList_measures_items(t *testing.T) {
+	root, list, created := newVirtualListTestRoot(1000, func(index int) float32 {
+		if index%2 == 0 {
+			return 10
+		}
+		return 30
+	})
+	list.EstimatedItemSize = 50
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// with the estimate only 4 items fill the window
+	assert.Equal(t, 4, *created)
+	assertFloatEqual(t, 80, list.ItemOffset(4))
+	assertFloatEqual(t, 80+996*50, root.LayoutGetContentHeight())
+
+	list.EstimatedItemSize = 0
+	list.SetWindow(0, 100)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	// measured items are reused, the rest is estimated as their average
+	assert.Equal(t, 5, *created)
+	assertLayout(t, list.Item(4), 0, 80, 100, 10)
+	assert.Nil(t, list.Item(5))
+	assertFloatEqual(t, 90+995*20, root.LayoutGetContentHeight())
+}
+
+func TestVirtualList_set_count_and_invalidate(t *testing.T) {
+	heights := map[int]float32{}
+	root, list, created := newVirtualListTestRoot(3, func(index int) float32 {
+		if height, ok := heights[index]; ok {
+			return height
+		}
+		return 20
+	})
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 3, *created)
+	assertFloatEqual(t, 200, root.LayoutGetContentHeight())
+
+	list.SetCount(20)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 10, *created)
+	assertFloatEqual(t, 400, root.LayoutGetContentHeight())
+
+	heights[1] = 50
+	old := list.Item(1)
+	list.Invalidate(1)
+	assert.Nil(t, old.Parent)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.NotSame(t, old, list.Item(1))
+	assertLayout(t, list.Item(2), 0, 70, 100, 20)
+	// the window was computed with the estimated size of the new item
+	assertLayout(t, list.Item(9), 0, 210, 100, 20)
+	assertFloatEqual(t, 430, root.LayoutGetContentHeight())
+
+	list.SetCount(2)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 4, len(root.Children))
+	assertFloatEqual(t, 200, root.LayoutGetContentHeight())
+}
+
+func TestVirtualList_rejects_children(t *testing.T) {
+	root := NewNode()
+	root.InsertChild(NewNode(), 0)
+	assert.Equal(t, ErrVirtualListNodeHasChildren, root.TrySetVirtualList(NewVirtualList(1, nil)))
+
+	list := NewNode()
+	list.SetVirtualList(NewVirtualList(0, nil))
+	assert.Equal(t, ErrVirtualListNodeCannotHaveChildren, list.TryInsertChild(NewNode(), 0))
+}
+
+func TestVirtualList_window_of_scroll_parent(t *testing.T) {
+	created := 0
+	list := NewVirtualList(1000, func(index int) *Node {
+		created++
+		return New(Height(20))
+	})
+	list.Overscan = 20
+	listNode := New(Padding(EdgeTop, 5))
+	listNode.SetVirtualList(list)
+	scroll := New(OverflowScroll, Width(100), Height(100), Padding(EdgeAll, 10), Children(listNode))
+	CalculateLayout(scroll, Undefined, Undefined, DirectionLTR)
+
+	// 80 points of the viewport and 20 points of overscan
+	assert.Equal(t, 5, created)
+	assertFloatEqual(t, 20005, listNode.LayoutGetHeight())
+	assertLayout(t, list.Item(4), 0, 85, 80, 20)
+	assert.Nil(t, list.Item(5))
+
+	list.SetWindow(500, Undefined)
+	CalculateLayout(scroll, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 11, created)
+	assert.Nil(t, list.Item(23))
+	assert.NotNil(t, list.Item(24))
+	assert.NotNil(t, list.Item(29))
+	assert.Nil(t, list.Item(30))
+
+	// without a viewport only items in overscan are created
+	created = 0
+	list = NewVirtualList(1000, func(index int) *Node {
+		created++
+		return New(Height(20))
+	})
+	list.Overscan = 40
+	root := New()
+	root.SetVirtualList(list)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 2, created)
+	assertFloatEqual(t, 20000, root.LayoutGetHeight())
+}
+
+func TestVirtualList_attach_and_detach(t *testing.T) {
+	root, list, created := newVirtualListTestRoot(100, func(int) float32 { return 20 })
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	item := list.Item(0)
+
+	other := NewNode()
+	assert.Equal(t, ErrVirtualListAttached, other.TrySetVirtualList(list))
+	assert.Nil(t, other.virtualList)
+	assert.NoError(t, root.TrySetVirtualList(list))
+
+	root.SetVirtualList(nil)
+	assert.Nil(t, root.virtualList)
+	assert.Equal(t, 0, len(root.Children))
+	assert.Nil(t, item.Parent)
+	assert.Nil(t, list.Item(0))
+	assert.True(t, root.IsDirty)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+	assertFloatEqual(t, 200, root.LayoutGetContentHeight())
+
+	// the detached list can be set on another node
+	other.StyleSetHeight(40)
+	other.SetVirtualList(list)
+	CalculateLayout(other, Undefined, Undefined, DirectionLTR)
+	assert.Equal(t, 12, *created)
+	assert.Same(t, other, list.Item(1).Parent)
+	assert.Nil(t, list.Item(2))
+}
+
+func TestVirtualList_detect_style_changes(t *testing.T) {
+	config := NewConfig()
+	config.DetectStyleChanges = true
+	var warnings []string
+	config.Logger = func(config *Config, node *Node, level LogLevel, format string, args ...interface{}) int {
+		warnings = append(warnings, format)
+		return 0
+	}
+	list := NewVirtualList(1000, func(index int) *Node {
+		return NewWithConfig(config, Height(20))
+	})
+	root := NewWithConfig(config, OverflowScroll, Width(100), Height(200))
+	root.SetVirtualList(list)
+	CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+
+	for _, offset := range []float32{100, 5000, 0} {
+		list.SetWindow(offset, Undefined)
+		CalculateLayout(root, Undefined, Undefined, DirectionLTR)
+		stats := CalculateLayoutWithStats(root, Undefined, Undefined, DirectionLTR)
+		assert.Equal(t, 0, stats.StyleChanges)
+		assert.False(t, root.IsDirty)
+	}
+	assert.Empty(t, warnings)
+}
@@ -3,6 +3,7 @@ package flex
 import (
 	"fmt"
 	"os"
+	"sync/atomic"
 )
 
 // CachedMeasurement describes measurements
@@ -29,6 +30,11 @@ type Layout struct {
 	Padding    [6]float32
 	Direction  Direction
 
+	// ContentDimensions is the size of the area scrolled by a scroll
+	// container: its padding box extended by margin boxes of its children,
+	// their visible overflow and the padding at its end
+	ContentDimensions [2]float32
+
 	computedFlexBasisGeneration int
 	computedFlexBasis           float32
 	HadOverflow                 bool
@@ -44,6 +50,14 @@ type Layout struct {
 	measuredDimensions [2]float32
 
 	cachedLayout CachedMeasurement
+
+	// collapsedMargins are margins of children of a block that collapse
+	// through its top and bottom edge, see nodeBlockLayoutImpl
+	collapsedMargins [2]blockMargin
+
+	// frame is left, top, width and height last reported to
+	// Config.OnLayoutChanged, Undefined before the first report
+	frame [4]float32
 }
 
 // Style describes CSS flexbox style of the node
@@ -66,10 +80,18 @@ type Style struct {
 	Position       [EdgeCount]Value
 	Padding        [EdgeCount]Value
 	Border         [EdgeCount]Value
+	Gap            [gutterCount]Value
 	Dimensions     [2]Value
 	MinDimensions  [2]Value
 	MaxDimensions  [2]Value
 
+	// Grid container properties, used with DisplayGrid
+	GridTemplateColumns []GridTrack
+	GridTemplateRows    []GridTrack
+	// Grid item properties
+	GridColumn GridPlacement
+	GridRow    GridPlacement
+
 	// Yoga specific properties, not compatible with flexbox specification
 	AspectRatio float32
 }
@@ -82,6 +104,28 @@ type Config struct {
 	PointScaleFactor          float32
 	Logger                    Logger
 	Context                   interface{}
+
+	// LogAssertions logs failed assertions as errors instead of panicking.
+	// The call that failed an assertion does nothing.
+	LogAssertions bool
+
+	// Tracer, if set, receives events from every CalculateLayout call. Trees
+	// sharing the config can be laid out concurrently, so it must be safe
+	// to call from several goroutines
+	Tracer LayoutTracer
+
+	// CloneNodeFunc, if set, clones shared children instead of Clone
+	CloneNodeFunc CloneNodeFunc
+
+	// DetectStyleChanges makes CalculateLayout compare style of every node
+	// with its style during the previous layout. Nodes with style changed by
+	// assigning to Style fields, instead of using StyleSetX, are marked dirty
+	// and logged as warnings
+	DetectStyleChanges bool
+
+	// OnLayoutChanged, if set, is called by CalculateLayout for every node
+	// whose position or size changed after rounding
+	OnLayoutChanged func(node *Node)
 }
 
 // Node describes a an element
@@ -98,6 +142,7 @@ type Node struct {
 	Measure  MeasureFunc
 	Baseline BaselineFunc
 	Print    PrintFunc
+	Dirtied  DirtiedFunc
 	Config   *Config
 	Context  interface{}
 
@@ -105,7 +150,22 @@ type Node struct {
 	hasNewLayout bool
 	NodeType     NodeType
 
+	// layoutVersion is incremented whenever node gets a new layout. Unlike
+	// hasNewLayout it's never reset, so it tracks changes for SpatialIndex
+	// without consuming HasNewLayout
+	layoutVersion uint64
+
 	resolvedDimensions [2]*Value
+
+	// measureFuncName is set by MeasureFuncRegistry.SetMeasureFunc
+	measureFuncName string
+
+	// layoutStyle is a copy of style used by the previous layout, set if
+	// Config.DetectStyleChanges is set
+	layoutStyle *Style
+
+	// virtualList creates children of node, see SetVirtualList
+	virtualList *VirtualList
 }
 
 var (
@@ -131,6 +191,12 @@ var (
 		undefinedValue,
 	}
 
+	defaultGutterValuesUnit = [gutterCount]Value{
+		undefinedValue,
+		undefinedValue,
+		undefinedValue,
+	}
+
 	defaultDimensionValues = [2]float32{
 		Undefined,
 		Undefined,
@@ -180,6 +246,7 @@ var (
 			Margin:         defaultEdgeValuesUnit,
 			Padding:        defaultEdgeValuesUnit,
 			Border:         defaultEdgeValuesUnit,
+			Gap:            defaultGutterValuesUnit,
 			AspectRatio:    Undefined,
 		},
 		Layout: Layout{
@@ -195,6 +262,8 @@ var (
 				computedWidth:     -1,
 				computedHeight:    -1,
 			},
+			// first computed frame is always reported
+			frame: [4]float32{Undefined, Undefined, Undefined, Undefined},
 		},
 	}
 
@@ -299,8 +368,20 @@ func NewNode() *Node {
 
 // Reset resets a node
 func (node *Node) Reset() {
-	assertWithNode(node, len(node.Children) == 0, "Cannot reset a node which still has children attached")
-	assertWithNode(node, node.Parent == nil, "Cannot reset a node still attached to a parent")
+	if err := node.TryReset(); err != nil {
+		assertFailed(node.Config, node, err.Error())
+	}
+}
+
+// TryReset resets a node, returns an error instead of panicking if node
+// still has children or a parent
+func (node *Node) TryReset() error {
+	if len(node.Children) != 0 {
+		return ErrResetNodeHasChildren
+	}
+	if node.Parent != nil {
+		return ErrResetNodeHasParent
+	}
 
 	node.Children = nil
 
@@ -311,6 +392,7 @@ func (node *Node) Reset() {
 		node.Style.AlignContent = AlignStretch
 	}
 	node.Config = config
+	return nil
 }
 
 // ConfigGetDefault returns default config, only for C#
@@ -332,10 +414,19 @@ func ConfigCopy(dest *Config, src *Config) {
 	*dest = *src
 }
 
+// nodeSetHasNewLayout marks that node was laid out or positioned
+func nodeSetHasNewLayout(node *Node) {
+	node.hasNewLayout = true
+	node.layoutVersion++
+}
+
 func nodeMarkDirtyInternal(node *Node) {
 	if !node.IsDirty {
 		node.IsDirty = true
 		node.Layout.computedFlexBasis = Undefined
+		if node.Dirtied != nil {
+			node.Dirtied(node)
+		}
 		if node.Parent != nil {
 			nodeMarkDirtyInternal(node.Parent)
 		}
@@ -344,25 +435,54 @@ func nodeMarkDirtyInternal(node *Node) {
 
 // SetMeasureFunc sets measure function
 func (node *Node) SetMeasureFunc(measureFunc MeasureFunc) {
+	if err := node.TrySetMeasureFunc(measureFunc); err != nil {
+		assertFailed(node.Config, node, err.Error())
+	}
+}
+
+// TrySetMeasureFunc sets measure function, returns an error instead of
+// panicking if node has children
+func (node *Node) TrySetMeasureFunc(measureFunc MeasureFunc) error {
 	if measureFunc == nil {
 		node.Measure = nil
+		node.measureFuncName = ""
 		// TODO: t18095186 Move nodeType to opt-in function and mark appropriate places in Litho
 		node.NodeType = NodeTypeDefault
 	} else {
-		assertWithNode(
-			node,
-			len(node.Children) == 0,
-			"Cannot set measure function: Nodes with measure functions cannot have children.")
+		if len(node.Children) != 0 {
+			return ErrMeasuredNodeHasChildren
+		}
 		node.Measure = measureFunc
+		node.measureFuncName = ""
 		// TODO: t18095186 Move nodeType to opt-in function and mark appropriate places in Litho
 		node.NodeType = NodeTypeText
 	}
+	return nil
 }
 
 // InsertChild inserts a child
 func (node *Node) InsertChild(child *Node, idx int) {
-	assertWithNode(node, child.Parent == nil, "Child already has a parent, it must be removed first.")
-	assertWithNode(node, node.Measure == nil, "Cannot add child: Nodes with measure functions cannot have children.")
+	if err := node.TryInsertChild(child, idx); err != nil {
+		assertFailed(node.Config, node, err.Error())
+	}
+}
+
+// TryInsertChild inserts a child, returns an error instead of panicking if
+// child already has a parent, node has a measure function or idx is out
+// of range
+func (node *Node) TryInsertChild(child *Node, idx int) error {
+	if child.Parent != nil {
+		return ErrChildHasParent
+	}
+	if node.Measure != nil {
+		return ErrMeasuredNodeCannotHaveChildren
+	}
+	if node.virtualList != nil {
+		return ErrVirtualListNodeCannotHaveChildren
+	}
+	if idx < 0 || idx > len(node.Children) {
+		return ErrChildIndexOutOfRange
+	}
 
 	a := node.Children
 	// https://github.com/golang/go/wiki/SliceTricks
@@ -372,6 +492,7 @@ func (node *Node) InsertChild(child *Node, idx int) {
 
 	child.Parent = node
 	nodeMarkDirtyInternal(node)
+	return nil
 }
 
 func (node *Node) deleteChild(child *Node) *Node {
@@ -393,8 +514,11 @@ func (node *Node) deleteChild(child *Node) *Node {
 // RemoveChild removes child node
 func (node *Node) RemoveChild(child *Node) {
 	if node.deleteChild(child) != nil {
-		child.Layout = nodeDefaults.Layout // layout is no longer valid
-		child.Parent = nil
+		// child shared with another tree stays in that tree
+		if child.Parent == node {
+			child.Layout = nodeDefaults.Layout // layout is no longer valid
+			child.Parent = nil
+		}
 		nodeMarkDirtyInternal(node)
 	}
 }
@@ -409,9 +533,19 @@ func (node *Node) GetChild(idx int) *Node {
 
 // MarkDirty marks node as dirty
 func (node *Node) MarkDirty() {
-	assertWithNode(node, node.Measure != nil,
-		"Only leaf nodes with custom measure functions should manually mark themselves as dirty")
+	if err := node.TryMarkDirty(); err != nil {
+		assertFailed(node.Config, node, err.Error())
+	}
+}
+
+// TryMarkDirty marks node as dirty, returns an error instead of panicking if
+// node doesn't have a measure function
+func (node *Node) TryMarkDirty() error {
+	if node.Measure == nil {
+		return ErrMarkDirtyWithoutMeasureFunc
+	}
 	nodeMarkDirtyInternal(node)
+	return nil
 }
 
 func styleEq(s1, s2 *Style) bool {
@@ -428,7 +562,12 @@ func styleEq(s1, s2 *Style) bool {
 		!feq(s1.Flex, s2.Flex) ||
 		!feq(s1.FlexGrow, s2.FlexGrow) ||
 		!feq(s1.FlexShrink, s2.FlexShrink) ||
-		!valueEq(s1.FlexBasis, s2.FlexBasis) {
+		!valueEq(s1.FlexBasis, s2.FlexBasis) ||
+		!feq(s1.AspectRatio, s2.AspectRatio) ||
+		!gridTracksEq(s1.GridTemplateColumns, s2.GridTemplateColumns) ||
+		!gridTracksEq(s1.GridTemplateRows, s2.GridTemplateRows) ||
+		s1.GridColumn != s2.GridColumn ||
+		s1.GridRow != s2.GridRow {
 		return false
 	}
 	for i := 0; i < EdgeCount; i++ {
@@ -439,6 +578,11 @@ func styleEq(s1, s2 *Style) bool {
 			return false
 		}
 	}
+	for i := 0; i < gutterCount; i++ {
+		if !valueEq(s1.Gap[i], s2.Gap[i]) {
+			return false
+		}
+	}
 	for i := 0; i < 2; i++ {
 		if !valueEq(s1.Dimensions[i], s2.Dimensions[i]) ||
 			!valueEq(s1.MinDimensions[i], s2.MinDimensions[i]) ||
@@ -525,9 +669,27 @@ func nodeResolveFlexBasisPtr(node *Node) *Value {
 // see yoga_props.go
 
 var (
-	currentGenerationCount = 0
+	// currentGenerationCount is only accessed atomically, see newLayoutContext
+	currentGenerationCount int64
 )
 
+// layoutContext holds the state of a single CalculateLayout call. Keeping it
+// out of package globals allows independent trees to be laid out concurrently.
+type layoutContext struct {
+	generationCount int
+	depth           int
+	stats           LayoutStats
+}
+
+func newLayoutContext() *layoutContext {
+	// Increment the generation count. This will force the recursive routine to
+	// visit all dirty nodes at least once. Subsequent visits will be skipped if
+	// the input parameters don't change.
+	return &layoutContext{
+		generationCount: int(atomic.AddInt64(&currentGenerationCount, 1)),
+	}
+}
+
 // FloatIsUndefined returns true if value is undefined
 func FloatIsUndefined(value float32) bool {
 	return IsNaN(value)
@@ -677,6 +839,21 @@ func nodePaddingAndBorderForAxis(node *Node, axis FlexDirection, widthSize float
 		nodeTrailingPaddingAndBorder(node, axis, widthSize)
 }
 
+// nodeGapForAxis returns the gap between items (or lines) laid out along axis.
+// A gap along a row axis separates columns, a gap along a column axis
+// separates rows.
+func nodeGapForAxis(node *Node, axis FlexDirection, axisSize float32) float32 {
+	gutter := GutterRow
+	if flexDirectionIsRow(axis) {
+		gutter = GutterColumn
+	}
+	gap := &node.Style.Gap[gutter]
+	if gap.Unit == UnitUndefined {
+		gap = &node.Style.Gap[GutterAll]
+	}
+	return fmaxf(resolveValue(gap, axisSize), 0)
+}
+
 func nodeAlignItem(node *Node, child *Node) Align {
 	align := child.Style.AlignSelf
 	if child.Style.AlignSelf == AlignAuto {
@@ -707,9 +884,7 @@ func Baseline(node *Node) float32 {
 	}
 
 	var baselineChild *Node
-	childCount := len(node.Children)
-	for i := 0; i < childCount; i++ {
-		child := node.GetChild(i)
+	for _, child := range nodeLayoutChildren(node) {
 		if child.lineIndex > 0 {
 			break
 		}
@@ -753,7 +928,7 @@ func flexDirectionCross(flexDirection FlexDirection, direction Direction) FlexDi
 }
 
 func nodeIsFlex(node *Node) bool {
-	return (node.Style.PositionType == PositionTypeRelative &&
+	return (node.Style.PositionType != PositionTypeAbsolute &&
 		(resolveFlexGrow(node) != 0 || nodeResolveFlexShrink(node) != 0))
 }
 
@@ -764,10 +939,8 @@ func isBaselineLayout(node *Node) bool {
 	if node.Style.AlignItems == AlignBaseline {
 		return true
 	}
-	childCount := len(node.Children)
-	for i := 0; i < childCount; i++ {
-		child := node.GetChild(i)
-		if child.Style.PositionType == PositionTypeRelative &&
+	for _, child := range nodeLayoutChildren(node) {
+		if child.Style.PositionType != PositionTypeAbsolute &&
 			child.Style.AlignSelf == AlignBaseline {
 			return true
 		}
@@ -899,6 +1072,9 @@ func nodeSetChildTrailingPosition(node *Node, child *Node, axis FlexDirection) {
 // If both left and right are defined, then use left. Otherwise return
 // +left or -right depending on which is defined.
 func nodeRelativePosition(node *Node, axis FlexDirection, axisSize float32) float32 {
+	if node.Style.PositionType == PositionTypeStatic {
+		return 0
+	}
 	if nodeIsLeadingPosDefined(node, axis) {
 		return nodeLeadingPosition(node, axis, axisSize)
 	}
@@ -953,7 +1129,8 @@ func nodeComputeFlexBasisForChild(node *Node,
 	parentHeight float32,
 	heightMode MeasureMode,
 	direction Direction,
-	config *Config) {
+	config *Config,
+	ctx *layoutContext) {
 	mainAxis := resolveFlexDirection(node.Style.FlexDirection, direction)
 	isMainAxisRow := flexDirectionIsRow(mainAxis)
 	mainAxisSize := height
@@ -975,7 +1152,7 @@ func nodeComputeFlexBasisForChild(node *Node,
 	if !FloatIsUndefined(resolvedFlexBasis) && !FloatIsUndefined(mainAxisSize) {
 		if FloatIsUndefined(child.Layout.computedFlexBasis) ||
 			(child.Config.IsExperimentalFeatureEnabled(ExperimentalFeatureWebFlexBasis) &&
-				child.Layout.computedFlexBasisGeneration != currentGenerationCount) {
+				child.Layout.computedFlexBasisGeneration != ctx.generationCount) {
 			child.Layout.computedFlexBasis =
 				fmaxf(resolvedFlexBasis, nodePaddingAndBorderForAxis(child, mainAxis, parentWidth))
 		}
@@ -1077,21 +1254,206 @@ func nodeComputeFlexBasisForChild(node *Node,
 			parentHeight,
 			false,
 			"measure",
-			config)
+			config,
+			ctx)
 
 		child.Layout.computedFlexBasis =
 			fmaxf(child.Layout.measuredDimensions[dim[mainAxis]],
 				nodePaddingAndBorderForAxis(child, mainAxis, parentWidth))
 	}
 
-	child.Layout.computedFlexBasisGeneration = currentGenerationCount
+	child.Layout.computedFlexBasisGeneration = ctx.generationCount
 }
 
-func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode MeasureMode, height float32, direction Direction, config *Config) {
+func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode MeasureMode, height float32, direction Direction, config *Config, ctx *layoutContext) {
 	mainAxis := resolveFlexDirection(node.Style.FlexDirection, direction)
 	crossAxis := flexDirectionCross(mainAxis, direction)
 	isMainAxisRow := flexDirectionIsRow(mainAxis)
 
+	nodeAbsoluteSizeChild(node, child, width, widthMode, height, direction, config, ctx)
+
+	if nodeIsTrailingPosDefined(child, mainAxis) && !nodeIsLeadingPosDefined(child, mainAxis) {
+		axisSize := height
+		if isMainAxisRow {
+			axisSize = width
+		}
+		child.Layout.Position[leading[mainAxis]] = node.Layout.measuredDimensions[dim[mainAxis]] -
+			child.Layout.measuredDimensions[dim[mainAxis]] -
+			nodeTrailingBorder(node, mainAxis) -
+			nodeTrailingMargin(child, mainAxis, width) -
+			nodeTrailingPosition(child, mainAxis, axisSize)
+	} else if !nodeIsLeadingPosDefined(child, mainAxis) &&
+		node.Style.JustifyContent == JustifyCenter {
+		child.Layout.Position[leading[mainAxis]] = (node.Layout.measuredDimensions[dim[mainAxis]] -
+			child.Layout.measuredDimensions[dim[mainAxis]]) /
+			2.0
+	} else if !nodeIsLeadingPosDefined(child, mainAxis) &&
+		node.Style.JustifyContent == JustifyFlexEnd {
+		child.Layout.Position[leading[mainAxis]] = (node.Layout.measuredDimensions[dim[mainAxis]] -
+			child.Layout.measuredDimensions[dim[mainAxis]])
+	}
+
+	if nodeIsTrailingPosDefined(child, crossAxis) &&
+		!nodeIsLeadingPosDefined(child, crossAxis) {
+		axisSize := width
+		if isMainAxisRow {
+			axisSize = height
+		}
+
+		child.Layout.Position[leading[crossAxis]] = node.Layout.measuredDimensions[dim[crossAxis]] -
+			child.Layout.measuredDimensions[dim[crossAxis]] -
+			nodeTrailingBorder(node, crossAxis) -
+			nodeTrailingMargin(child, crossAxis, width) -
+			nodeTrailingPosition(child, crossAxis, axisSize)
+	} else if !nodeIsLeadingPosDefined(child, crossAxis) &&
+		nodeAlignItem(node, child) == AlignCenter {
+		child.Layout.Position[leading[crossAxis]] =
+			(node.Layout.measuredDimensions[dim[crossAxis]] -
+				child.Layout.measuredDimensions[dim[crossAxis]]) /
+				2.0
+	} else if !nodeIsLeadingPosDefined(child, crossAxis) &&
+		((nodeAlignItem(node, child) == AlignFlexEnd) != (node.Style.FlexWrap == WrapWrapReverse)) {
+		child.Layout.Position[leading[crossAxis]] = (node.Layout.measuredDimensions[dim[crossAxis]] -
+			child.Layout.measuredDimensions[dim[crossAxis]])
+	}
+}
+
+// nodeIsContainingBlock returns true if absolute descendants of node are
+// positioned relative to it. The root is always a containing block.
+func nodeIsContainingBlock(node *Node) bool {
+	return node.Style.PositionType != PositionTypeStatic || node.Parent == nil
+}
+
+// nodeAbsoluteLayoutDescendants lays out absolute children of static parent,
+// and of its static descendants, against containing block node. offsetLeft and
+// offsetTop are the position of parent in node
+func nodeAbsoluteLayoutDescendants(node *Node, parent *Node, offsetLeft float32, offsetTop float32, width float32, widthMode MeasureMode, height float32, direction Direction, config *Config, ctx *layoutContext) {
+	nodeCloneChildrenIfNeeded(parent)
+	for _, child := range nodeLayoutChildren(parent) {
+		if child.Style.Display == DisplayNone {
+			continue
+		}
+		switch child.Style.PositionType {
+		case PositionTypeAbsolute:
+			nodeAbsoluteSizeChild(node, child, width, widthMode, height, direction, config, ctx)
+			nodeAbsolutePositionDescendant(node, parent, child, offsetLeft, offsetTop, width, height, direction)
+		case PositionTypeStatic:
+			nodeAbsoluteLayoutDescendants(node,
+				child,
+				offsetLeft+child.Layout.Position[EdgeLeft],
+				offsetTop+child.Layout.Position[EdgeTop],
+				width,
+				widthMode,
+				height,
+				direction,
+				config,
+				ctx)
+		}
+	}
+}
+
+// nodeAbsolutePositionDescendant positions absolute child of static parent.
+// Insets are relative to containing block node. Without insets the child
+// takes its static position in parent
+func nodeAbsolutePositionDescendant(node *Node, parent *Node, child *Node, offsetLeft float32, offsetTop float32, width float32, height float32, direction Direction) {
+	for _, axis := range []FlexDirection{resolveFlexDirection(FlexDirectionRow, direction), FlexDirectionColumn} {
+		axisSize, offset := height, offsetTop
+		if flexDirectionIsRow(axis) {
+			axisSize, offset = width, offsetLeft
+		}
+		isReverse := axis == FlexDirectionRowReverse
+		nodeSize := node.Layout.measuredDimensions[dim[axis]]
+		parentSize := parent.Layout.measuredDimensions[dim[axis]]
+		childSize := child.Layout.measuredDimensions[dim[axis]]
+
+		// distance of parent from the leading edge of node
+		parentLead := offset
+		if isReverse {
+			parentLead = nodeSize - offset - parentSize
+		}
+
+		// distance of child from the leading edge of parent
+		var lead float32
+		if nodeIsLeadingPosDefined(child, axis) {
+			lead = nodeLeadingPosition(child, axis, axisSize) +
+				nodeLeadingBorder(node, axis) +
+				nodeLeadingMargin(child, axis, width) -
+				parentLead
+		} else if nodeIsTrailingPosDefined(child, axis) {
+			lead = nodeSize - childSize -
+				nodeTrailingBorder(node, axis) -
+				nodeTrailingMargin(child, axis, width) -
+				nodeTrailingPosition(child, axis, axisSize) -
+				parentLead
+		} else {
+			lead = nodeStaticPositionLead(parent, child, axis, width, direction)
+		}
+
+		physicalAxis := FlexDirectionColumn
+		if flexDirectionIsRow(axis) {
+			physicalAxis = FlexDirectionRow
+		}
+		position := lead
+		if isReverse {
+			position = parentSize - lead - childSize
+		}
+		child.Layout.Position[pos[physicalAxis]] = position
+		child.Layout.Position[trailing[physicalAxis]] = parentSize - childSize - position
+	}
+}
+
+// nodeStaticPositionLead returns distance of absolute child without insets
+// from the leading edge of parent along axis. It's aligned by justify-content
+// and align-items of parent the same way as absolute children of a parent
+// that isn't static, see nodeAbsoluteLayoutChild
+func nodeStaticPositionLead(parent *Node, child *Node, axis FlexDirection, width float32, direction Direction) float32 {
+	mainAxis := resolveFlexDirection(parent.Style.FlexDirection, direction)
+	isMainAxis := flexDirectionIsRow(mainAxis) == flexDirectionIsRow(axis)
+	flexAxis := mainAxis
+	if !isMainAxis {
+		flexAxis = flexDirectionCross(mainAxis, direction)
+	}
+	parentSize := parent.Layout.measuredDimensions[dim[axis]]
+	childSize := child.Layout.measuredDimensions[dim[axis]]
+	align := nodeAlignItem(parent, child)
+
+	var lead float32
+	if (isMainAxis && parent.Style.JustifyContent == JustifyCenter) ||
+		(!isMainAxis && align == AlignCenter) {
+		lead = (parentSize - childSize) / 2.0
+	} else if (isMainAxis && parent.Style.JustifyContent == JustifyFlexEnd) ||
+		(!isMainAxis && (align == AlignFlexEnd) != (parent.Style.FlexWrap == WrapWrapReverse)) {
+		lead = parentSize - childSize
+	} else {
+		lead = nodeLayoutLeadingPaddingAndBorder(parent, flexAxis, direction) +
+			nodeLeadingMargin(child, flexAxis, width)
+	}
+	if flexAxis != axis {
+		// the flex axis of parent is reversed relative to axis
+		lead = parentSize - childSize - lead
+	}
+	return lead
+}
+
+// nodeLayoutLeadingPaddingAndBorder returns padding and border of node on the
+// leading edge of axis, computed by the layout of node
+func nodeLayoutLeadingPaddingAndBorder(node *Node, axis FlexDirection, direction Direction) float32 {
+	edge := leading[axis]
+	if flexDirectionIsRow(axis) {
+		edge = EdgeStart
+		if axis != resolveFlexDirection(FlexDirectionRow, direction) {
+			edge = EdgeEnd
+		}
+	}
+	return node.Layout.Padding[edge] + node.Layout.Border[edge]
+}
+
+// nodeAbsoluteSizeChild lays out absolute child with the size resolved
+// against its containing block node
+func nodeAbsoluteSizeChild(node *Node, child *Node, width float32, widthMode MeasureMode, height float32, direction Direction, config *Config, ctx *layoutContext) {
+	mainAxis := resolveFlexDirection(node.Style.FlexDirection, direction)
+	isMainAxisRow := flexDirectionIsRow(mainAxis)
+
 	childWidth := Undefined
 	childHeight := Undefined
 	childWidthMeasureMode := MeasureModeUndefined
@@ -1181,7 +1543,8 @@ func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode M
 			childHeight,
 			false,
 			"abs-measure",
-			config)
+			config,
+			ctx)
 		childWidth = child.Layout.measuredDimensions[DimensionWidth] +
 			nodeMarginForAxis(child, FlexDirectionRow, width)
 		childHeight = child.Layout.measuredDimensions[DimensionHeight] +
@@ -1198,56 +1561,12 @@ func nodeAbsoluteLayoutChild(node *Node, child *Node, width float32, widthMode M
 		childHeight,
 		true,
 		"abs-layout",
-		config)
-
-	if nodeIsTrailingPosDefined(child, mainAxis) && !nodeIsLeadingPosDefined(child, mainAxis) {
-		axisSize := height
-		if isMainAxisRow {
-			axisSize = width
-		}
-		child.Layout.Position[leading[mainAxis]] = node.Layout.measuredDimensions[dim[mainAxis]] -
-			child.Layout.measuredDimensions[dim[mainAxis]] -
-			nodeTrailingBorder(node, mainAxis) -
-			nodeTrailingMargin(child, mainAxis, width) -
-			nodeTrailingPosition(child, mainAxis, axisSize)
-	} else if !nodeIsLeadingPosDefined(child, mainAxis) &&
-		node.Style.JustifyContent == JustifyCenter {
-		child.Layout.Position[leading[mainAxis]] = (node.Layout.measuredDimensions[dim[mainAxis]] -
-			child.Layout.measuredDimensions[dim[mainAxis]]) /
-			2.0
-	} else if !nodeIsLeadingPosDefined(child, mainAxis) &&
-		node.Style.JustifyContent == JustifyFlexEnd {
-		child.Layout.Position[leading[mainAxis]] = (node.Layout.measuredDimensions[dim[mainAxis]] -
-			child.Layout.measuredDimensions[dim[mainAxis]])
-	}
-
-	if nodeIsTrailingPosDefined(child, crossAxis) &&
-		!nodeIsLeadingPosDefined(child, crossAxis) {
-		axisSize := width
-		if isMainAxisRow {
-			axisSize = height
-		}
-
-		child.Layout.Position[leading[crossAxis]] = node.Layout.measuredDimensions[dim[crossAxis]] -
-			child.Layout.measuredDimensions[dim[crossAxis]] -
-			nodeTrailingBorder(node, crossAxis) -
-			nodeTrailingMargin(child, crossAxis, width) -
-			nodeTrailingPosition(child, crossAxis, axisSize)
-	} else if !nodeIsLeadingPosDefined(child, crossAxis) &&
-		nodeAlignItem(node, child) == AlignCenter {
-		child.Layout.Position[leading[crossAxis]] =
-			(node.Layout.measuredDimensions[dim[crossAxis]] -
-				child.Layout.measuredDimensions[dim[crossAxis]]) /
-				2.0
-	} else if !nodeIsLeadingPosDefined(child, crossAxis) &&
-		((nodeAlignItem(node, child) == AlignFlexEnd) != (node.Style.FlexWrap == WrapWrapReverse)) {
-		child.Layout.Position[leading[crossAxis]] = (node.Layout.measuredDimensions[dim[crossAxis]] -
-			child.Layout.measuredDimensions[dim[crossAxis]])
-	}
+		config,
+		ctx)
 }
 
 // nodeWithMeasureFuncSetMeasuredDimensions sets measure dimensions for node with measure func
-func nodeWithMeasureFuncSetMeasuredDimensions(node *Node, availableWidth float32, availableHeight float32, widthMeasureMode MeasureMode, heightMeasureMode MeasureMode, parentWidth float32, parentHeight float32) {
+func nodeWithMeasureFuncSetMeasuredDimensions(node *Node, availableWidth float32, availableHeight float32, widthMeasureMode MeasureMode, heightMeasureMode MeasureMode, parentWidth float32, parentHeight float32, ctx *layoutContext) {
 	assertWithNode(node, node.Measure != nil, "Expected node to have custom measure function")
 
 	paddingAndBorderAxisRow := nodePaddingAndBorderForAxis(node, FlexDirectionRow, availableWidth)
@@ -1274,6 +1593,7 @@ func nodeWithMeasureFuncSetMeasuredDimensions(node *Node, availableWidth float32
 	} else {
 		// Measure the text under the current raints.
 		measuredSize := node.Measure(node, innerWidth, widthMeasureMode, innerHeight, heightMeasureMode)
+		ctx.stats.MeasureFuncCalls++
 
 		width := availableWidth - marginAxisRow
 		if widthMeasureMode == MeasureModeUndefined ||
@@ -1349,10 +1669,116 @@ func nodeFixedSizeSetMeasuredDimensions(node *Node,
 	return false
 }
 
+// nodeSizeForContent returns the measured size of node on axis for content of
+// contentSize. Exact size is used as is, at most size limits content size
+func nodeSizeForContent(node *Node, axis FlexDirection, contentSize float32, available float32, measureMode MeasureMode, parentAxisSize float32, parentWidth float32) float32 {
+	if measureMode == MeasureModeExactly {
+		return nodeBoundAxis(node, axis, available, parentAxisSize, parentWidth)
+	}
+	size := contentSize + nodePaddingAndBorderForAxis(node, axis, parentWidth)
+	if measureMode == MeasureModeAtMost {
+		size = fminf(size, available)
+	}
+	return nodeBoundAxis(node, axis, size, parentAxisSize, parentWidth)
+}
+
+// nodeMeasureChild returns the outer size of child on axis. child stretches to
+// areaWidth, unless it's Undefined or child has a width. minContent measures
+// child with no available width, otherwise it gets its max-content width
+func nodeMeasureChild(child *Node, axis FlexDirection, areaWidth float32, minContent bool, innerWidth float32, innerHeight float32, direction Direction, reason string, config *Config, ctx *layoutContext) float32 {
+	marginRow := nodeMarginForAxis(child, FlexDirectionRow, innerWidth)
+	marginColumn := nodeMarginForAxis(child, FlexDirectionColumn, innerWidth)
+
+	childWidth, childWidthMeasureMode := Undefined, MeasureModeUndefined
+	childHeight, childHeightMeasureMode := Undefined, MeasureModeUndefined
+	if nodeIsStyleDimDefined(child, FlexDirectionRow, innerWidth) {
+		childWidth = resolveValue(child.resolvedDimensions[DimensionWidth], innerWidth) + marginRow
+		childWidthMeasureMode = MeasureModeExactly
+	} else if !FloatIsUndefined(areaWidth) {
+		childWidth = areaWidth
+		childWidthMeasureMode = MeasureModeExactly
+	} else if minContent {
+		childWidth = marginRow
+		childWidthMeasureMode = MeasureModeAtMost
+	}
+	if nodeIsStyleDimDefined(child, FlexDirectionColumn, innerHeight) {
+		childHeight = resolveValue(child.resolvedDimensions[DimensionHeight], innerHeight) + marginColumn
+		childHeightMeasureMode = MeasureModeExactly
+	}
+	constrainMaxSizeForMode(child, FlexDirectionRow, innerWidth, innerWidth, &childWidthMeasureMode, &childWidth)
+	constrainMaxSizeForMode(child, FlexDirectionColumn, innerHeight, innerWidth, &childHeightMeasureMode, &childHeight)
+
+	layoutNodeInternal(child,
+		childWidth,
+		childHeight,
+		direction,
+		childWidthMeasureMode,
+		childHeightMeasureMode,
+		innerWidth,
+		innerHeight,
+		false,
+		reason,
+		config,
+		ctx)
+
+	return child.Layout.measuredDimensions[dim[axis]] + nodeMarginForAxis(child, axis, innerWidth)
+}
+
+// nodeSetContentDimensions sets content dimensions of node from layout of its
+// children. Children overflowing the start edge can't be scrolled to and
+// don't count
+func nodeSetContentDimensions(node *Node) {
+	if node.Style.Display == DisplayContents {
+		node.Layout.ContentDimensions = [2]float32{}
+		return
+	}
+	rtl := node.Layout.Direction == DirectionRTL
+	borderLeft := node.LayoutGetBorder(EdgeLeft)
+	borderTop := node.LayoutGetBorder(EdgeTop)
+	width := node.Layout.Dimensions[DimensionWidth] - borderLeft - node.LayoutGetBorder(EdgeRight)
+	height := node.Layout.Dimensions[DimensionHeight] - borderTop - node.LayoutGetBorder(EdgeBottom)
+
+	var left, right, bottom float32
+	right, bottom = width, height
+	for _, child := range nodeLayoutChildren(node) {
+		if child.Style.Display == DisplayNone {
+			continue
+		}
+		childLeft := child.Layout.Position[EdgeLeft] - borderLeft
+		childTop := child.Layout.Position[EdgeTop] - borderTop
+		childRight := childLeft + child.Layout.Dimensions[DimensionWidth]
+		childBottom := childTop + child.Layout.Dimensions[DimensionHeight]
+		if child.Style.Overflow == OverflowVisible {
+			// content of child overflows its padding box in the same direction
+			childPaddingLeft := childLeft + child.LayoutGetBorder(EdgeLeft)
+			childTop += child.LayoutGetBorder(EdgeTop)
+			if rtl {
+				childLeft = fminf(childLeft, childPaddingLeft+child.Layout.Dimensions[DimensionWidth]-
+					child.LayoutGetBorder(EdgeLeft)-child.LayoutGetBorder(EdgeRight)-child.Layout.ContentDimensions[DimensionWidth])
+			} else {
+				childRight = fmaxf(childRight, childPaddingLeft+child.Layout.ContentDimensions[DimensionWidth])
+			}
+			childBottom = fmaxf(childBottom, childTop+child.Layout.ContentDimensions[DimensionHeight])
+		}
+		left = fminf(left, childLeft-child.LayoutGetMargin(EdgeLeft)-node.LayoutGetPadding(EdgeLeft))
+		right = fmaxf(right, childRight+child.LayoutGetMargin(EdgeRight)+node.LayoutGetPadding(EdgeRight))
+		bottom = fmaxf(bottom, childBottom+child.LayoutGetMargin(EdgeBottom)+node.LayoutGetPadding(EdgeBottom))
+	}
+
+	if rtl {
+		node.Layout.ContentDimensions[DimensionWidth] = width - left
+	} else {
+		node.Layout.ContentDimensions[DimensionWidth] = right
+	}
+	node.Layout.ContentDimensions[DimensionHeight] = bottom
+}
+
 // zeroOutLayoutRecursivly zeros out layout recursively
 func zeroOutLayoutRecursivly(node *Node) {
 	node.Layout.Dimensions[DimensionHeight] = 0
 	node.Layout.Dimensions[DimensionWidth] = 0
+	node.Layout.ContentDimensions[DimensionHeight] = 0
+	node.Layout.ContentDimensions[DimensionWidth] = 0
 	node.Layout.Position[EdgeTop] = 0
 	node.Layout.Position[EdgeBottom] = 0
 	node.Layout.Position[EdgeLeft] = 0
@@ -1363,7 +1789,7 @@ func zeroOutLayoutRecursivly(node *Node) {
 	node.Layout.cachedLayout.widthMeasureMode = MeasureModeExactly
 	node.Layout.cachedLayout.computedWidth = 0
 	node.Layout.cachedLayout.computedHeight = 0
-	node.hasNewLayout = true
+	nodeSetHasNewLayout(node)
 	childCount := len(node.Children)
 	for i := 0; i < childCount; i++ {
 		child := node.Children[i]
@@ -1371,6 +1797,51 @@ func zeroOutLayoutRecursivly(node *Node) {
 	}
 }
 
+// zeroOutContentsLayout zeros out layout of display: contents node and of its
+// display: contents children. Other children are laid out by the parent
+func zeroOutContentsLayout(node *Node) {
+	node.Layout.Dimensions[DimensionHeight] = 0
+	node.Layout.Dimensions[DimensionWidth] = 0
+	node.Layout.Position[EdgeTop] = 0
+	node.Layout.Position[EdgeBottom] = 0
+	node.Layout.Position[EdgeLeft] = 0
+	node.Layout.Position[EdgeRight] = 0
+	node.Layout.measuredDimensions[DimensionWidth] = 0
+	node.Layout.measuredDimensions[DimensionHeight] = 0
+	nodeSetHasNewLayout(node)
+	node.IsDirty = false
+	nodeCloneChildrenIfNeeded(node)
+	for _, child := range node.Children {
+		if child.Style.Display == DisplayContents {
+			zeroOutContentsLayout(child)
+		}
+	}
+}
+
+// nodeLayoutChildren returns children laid out by node: display: contents
+// children are replaced by their own layout children
+func nodeLayoutChildren(node *Node) []*Node {
+	hasContents := false
+	for _, child := range node.Children {
+		if child.Style.Display == DisplayContents {
+			hasContents = true
+			break
+		}
+	}
+	if !hasContents {
+		return node.Children
+	}
+	var children []*Node
+	for _, child := range node.Children {
+		if child.Style.Display == DisplayContents {
+			children = append(children, nodeLayoutChildren(child)...)
+		} else {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
 // This is the main routine that implements a subset of the flexbox layout
 // algorithm
 // described in the W3C YG documentation: https://www.w3.org/TR/YG3-flexbox/.
@@ -1458,7 +1929,7 @@ func zeroOutLayoutRecursivly(node *Node) {
 func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 	parentDirection Direction, widthMeasureMode MeasureMode,
 	heightMeasureMode MeasureMode, parentWidth float32, parentHeight float32,
-	performLayout bool, config *Config) {
+	performLayout bool, config *Config, ctx *layoutContext) {
 	// assertWithNode(node, YGFloatIsUndefined(availableWidth) ? widthMeasureMode == YGMeasureModeUndefined : true, "availableWidth is indefinite so widthMeasureMode must be YGMeasureModeUndefined");
 	//assertWithNode(node, YGFloatIsUndefined(availableHeight) ? heightMeasureMode == YGMeasureModeUndefined : true, "availableHeight is indefinite so heightMeasureMode must be YGMeasureModeUndefined");
 
@@ -1485,11 +1956,22 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 	node.Layout.Padding[EdgeBottom] = nodeTrailingPadding(node, flexColumnDirection, parentWidth)
 
 	if node.Measure != nil {
-		nodeWithMeasureFuncSetMeasuredDimensions(node, availableWidth, availableHeight, widthMeasureMode, heightMeasureMode, parentWidth, parentHeight)
+		nodeWithMeasureFuncSetMeasuredDimensions(node, availableWidth, availableHeight, widthMeasureMode, heightMeasureMode, parentWidth, parentHeight, ctx)
 		return
 	}
 
-	childCount := len(node.Children)
+	if node.virtualList != nil {
+		node.virtualList.materialize(parentWidth, parentHeight)
+	}
+	nodeCloneChildrenIfNeeded(node)
+	for _, child := range node.Children {
+		if child.Style.Display == DisplayContents {
+			zeroOutContentsLayout(child)
+		}
+	}
+
+	children := nodeLayoutChildren(node)
+	childCount := len(children)
 	if childCount == 0 {
 		nodeEmptyContainerSetMeasuredDimensions(node, availableWidth, availableHeight, widthMeasureMode, heightMeasureMode, parentWidth, parentHeight)
 		return
@@ -1501,6 +1983,15 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 		return
 	}
 
+	switch node.Style.Display {
+	case DisplayGrid:
+		nodeGridLayoutImpl(node, children, availableWidth, availableHeight, direction, widthMeasureMode, heightMeasureMode, parentWidth, parentHeight, performLayout, config, ctx)
+		return
+	case DisplayBlock, DisplayInlineBlock:
+		nodeBlockLayoutImpl(node, children, availableWidth, availableHeight, direction, widthMeasureMode, heightMeasureMode, parentWidth, parentHeight, performLayout, config, ctx)
+		return
+	}
+
 	// Reset layout flags, as they could have changed.
 	node.Layout.HadOverflow = false
 
@@ -1583,13 +2074,16 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 		availableInnerCrossDim = availableInnerHeight
 	}
 
+	mainAxisGap := nodeGapForAxis(node, mainAxis, availableInnerMainDim)
+	crossAxisGap := nodeGapForAxis(node, crossAxis, availableInnerCrossDim)
+
 	// If there is only one child with flexGrow + flexShrink it means we can set the
 	// computedFlexBasis to 0 instead of measuring and shrinking / flexing the child to exactly
 	// match the remaining space
 	var singleFlexChild *Node
 	if measureModeMainDim == MeasureModeExactly {
 		for i := 0; i < childCount; i++ {
-			child := node.GetChild(i)
+			child := children[i]
 			if singleFlexChild != nil {
 				if nodeIsFlex(child) {
 					// There is already a flexible child, abort.
@@ -1603,13 +2097,14 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 	}
 
 	var totalOuterFlexBasis float32
+	flowChildCount := 0
 
 	// STEP 3: DETERMINE FLEX BASIS FOR EACH ITEM
 	for i := 0; i < childCount; i++ {
-		child := node.Children[i]
+		child := children[i]
 		if child.Style.Display == DisplayNone {
 			zeroOutLayoutRecursivly(child)
-			child.hasNewLayout = true
+			nodeSetHasNewLayout(child)
 			child.IsDirty = false
 			continue
 		}
@@ -1639,7 +2134,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			child.NextChild = nil
 		} else {
 			if child == singleFlexChild {
-				child.Layout.computedFlexBasisGeneration = currentGenerationCount
+				child.Layout.computedFlexBasisGeneration = ctx.generationCount
 				child.Layout.computedFlexBasis = 0
 			} else {
 				nodeComputeFlexBasisForChild(node,
@@ -1651,13 +2146,21 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 					availableInnerHeight,
 					heightMeasureMode,
 					direction,
-					config)
+					config,
+					ctx)
 			}
 		}
 
 		totalOuterFlexBasis +=
 			child.Layout.computedFlexBasis + nodeMarginForAxis(child, mainAxis, availableInnerWidth)
 
+		if child.Style.PositionType != PositionTypeAbsolute {
+			if flowChildCount > 0 {
+				totalOuterFlexBasis += mainAxisGap
+			}
+			flowChildCount++
+		}
+
 	}
 
 	flexBasisOverflows := totalOuterFlexBasis > availableInnerMainDim
@@ -1706,7 +2209,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 
 		// Add items to the current line until it's full or we run out of items.
 		for i := startOfLineIndex; i < childCount; i++ {
-			child := node.Children[i]
+			child := children[i]
 			if child.Style.Display == DisplayNone {
 				endOfLineIndex++
 				continue
@@ -1718,20 +2221,27 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 				flexBasisWithMaxConstraints := fminf(resolveValue(&child.Style.MaxDimensions[dim[mainAxis]], mainAxisParentSize), child.Layout.computedFlexBasis)
 				flexBasisWithMinAndMaxConstraints := fmaxf(resolveValue(&child.Style.MinDimensions[dim[mainAxis]], mainAxisParentSize), flexBasisWithMaxConstraints)
 
+				// The gap only separates items, so the first item on a line has none.
+				var childLeadingGapMainAxis float32
+				if itemsOnLine > 0 {
+					childLeadingGapMainAxis = mainAxisGap
+				}
+
 				// If this is a multi-line flow and this item pushes us over the
 				// available size, we've
 				// hit the end of the current line. Break out of the loop and lay out
 				// the current line.
 				if sizeConsumedOnCurrentLineIncludingMinConstraint+flexBasisWithMinAndMaxConstraints+
-					childMarginMainAxis >
+					childMarginMainAxis+childLeadingGapMainAxis >
 					availableInnerMainDim &&
 					isNodeFlexWrap && itemsOnLine > 0 {
 					break
 				}
 
 				sizeConsumedOnCurrentLineIncludingMinConstraint +=
-					flexBasisWithMinAndMaxConstraints + childMarginMainAxis
-				sizeConsumedOnCurrentLine += flexBasisWithMinAndMaxConstraints + childMarginMainAxis
+					flexBasisWithMinAndMaxConstraints + childMarginMainAxis + childLeadingGapMainAxis
+				sizeConsumedOnCurrentLine += flexBasisWithMinAndMaxConstraints + childMarginMainAxis +
+					childLeadingGapMainAxis
 				itemsOnLine++
 
 				if nodeIsFlex(child) {
@@ -2061,7 +2571,8 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 					availableInnerHeight,
 					performLayout && !requiresStretchLayout,
 					"flex",
-					config)
+					config,
+					ctx)
 				if currentRelativeChild.Layout.HadOverflow {
 					node.Layout.HadOverflow = true
 				}
@@ -2101,8 +2612,8 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 
 		numberOfAutoMarginsOnCurrentLine := 0
 		for i := startOfLineIndex; i < endOfLineIndex; i++ {
-			child := node.Children[i]
-			if child.Style.PositionType == PositionTypeRelative {
+			child := children[i]
+			if child.Style.PositionType != PositionTypeAbsolute {
 				if marginLeadingValue(child, mainAxis).Unit == UnitAuto {
 					numberOfAutoMarginsOnCurrentLine++
 				}
@@ -2128,15 +2639,20 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 				// Space on the edges is half of the space between elements
 				betweenMainDim = remainingFreeSpace / float32(itemsOnLine)
 				leadingMainDim = betweenMainDim / 2
+			case JustifySpaceEvenly:
+				// Space on the edges is the same as the space between elements
+				betweenMainDim = remainingFreeSpace / float32(itemsOnLine+1)
+				leadingMainDim = betweenMainDim
 			case JustifyFlexStart:
 			}
 		}
 
 		mainDim := leadingPaddingAndBorderMain + leadingMainDim
 		var crossDim float32
+		placedItemsOnLine := 0
 
 		for i := startOfLineIndex; i < endOfLineIndex; i++ {
-			child := node.Children[i]
+			child := children[i]
 			if child.Style.Display == DisplayNone {
 				continue
 			}
@@ -2155,7 +2671,12 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 				// Now that we placed the element, we need to update the variables.
 				// We need to do that only for relative elements. Absolute elements
 				// do not take part in that phase.
-				if child.Style.PositionType == PositionTypeRelative {
+				if child.Style.PositionType != PositionTypeAbsolute {
+					if placedItemsOnLine > 0 {
+						mainDim += mainAxisGap
+					}
+					placedItemsOnLine++
+
 					if marginLeadingValue(child, mainAxis).Unit == UnitAuto {
 						mainDim += remainingFreeSpace / float32(numberOfAutoMarginsOnCurrentLine)
 					}
@@ -2217,11 +2738,16 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			parentWidth) -
 			paddingAndBorderAxisCross
 
+		// Lines are separated by the gap on the cross axis.
+		if lineCount > 0 {
+			totalLineCrossDim += crossAxisGap
+		}
+
 		// STEP 7: CROSS-AXIS ALIGNMENT
 		// We can skip child alignment if we're just measuring the container.
 		if performLayout {
 			for i := startOfLineIndex; i < endOfLineIndex; i++ {
-				child := node.Children[i]
+				child := children[i]
 				if child.Style.Display == DisplayNone {
 					continue
 				}
@@ -2315,7 +2841,8 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 								availableInnerHeight,
 								true,
 								"stretch",
-								config)
+								config,
+								ctx)
 						}
 					} else {
 						remainingCrossDim := containerCrossAxis - nodeDimWithMargin(child, crossAxis, availableInnerWidth)
@@ -2379,6 +2906,15 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			if availableInnerCrossDim > totalLineCrossDim && lineCount > 1 {
 				crossDimLead = remainingAlignContentDim / float32(lineCount-1)
 			}
+		case AlignSpaceEvenly:
+			if availableInnerCrossDim > totalLineCrossDim {
+				currentLead += remainingAlignContentDim / float32(lineCount+1)
+				if lineCount > 1 {
+					crossDimLead = remainingAlignContentDim / float32(lineCount+1)
+				}
+			} else {
+				currentLead += remainingAlignContentDim / 2
+			}
 		case AlignAuto:
 		case AlignFlexStart:
 		case AlignBaseline:
@@ -2389,16 +2925,20 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 			startIndex := endIndex
 			var ii int
 
+			if i > 0 {
+				currentLead += crossAxisGap
+			}
+
 			// compute the line's height and find the endIndex
 			var lineHeight float32
 			var maxAscentForCurrentLine float32
 			var maxDescentForCurrentLine float32
 			for ii = startIndex; ii < childCount; ii++ {
-				child := node.Children[ii]
+				child := children[ii]
 				if child.Style.Display == DisplayNone {
 					continue
 				}
-				if child.Style.PositionType == PositionTypeRelative {
+				if child.Style.PositionType != PositionTypeAbsolute {
 					if child.lineIndex != i {
 						break
 					}
@@ -2421,11 +2961,11 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 
 			if performLayout {
 				for ii = startIndex; ii < endIndex; ii++ {
-					child := node.Children[ii]
+					child := children[ii]
 					if child.Style.Display == DisplayNone {
 						continue
 					}
-					if child.Style.PositionType == PositionTypeRelative {
+					if child.Style.PositionType != PositionTypeAbsolute {
 						switch nodeAlignItem(node, child) {
 						case AlignFlexStart:
 							{
@@ -2478,7 +3018,8 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 											availableInnerHeight,
 											true,
 											"multiline-stretch",
-											config)
+											config,
+											ctx)
 									}
 								}
 							}
@@ -2491,6 +3032,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 						case AlignAuto:
 						case AlignSpaceBetween:
 						case AlignSpaceAround:
+						case AlignSpaceEvenly:
 						}
 					}
 				}
@@ -2546,8 +3088,8 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 	// As we only wrapped in normal direction yet, we need to reverse the positions on wrap-reverse.
 	if performLayout && node.Style.FlexWrap == WrapWrapReverse {
 		for i := 0; i < childCount; i++ {
-			child := node.GetChild(i)
-			if child.Style.PositionType == PositionTypeRelative {
+			child := children[i]
+			if child.Style.PositionType != PositionTypeAbsolute {
 				child.Layout.Position[pos[crossAxis]] = node.Layout.measuredDimensions[dim[crossAxis]] -
 					child.Layout.Position[pos[crossAxis]] -
 					child.Layout.measuredDimensions[dim[crossAxis]]
@@ -2556,20 +3098,24 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 	}
 
 	if performLayout {
-		// STEP 10: SIZING AND POSITIONING ABSOLUTE CHILDREN
-		for currentAbsoluteChild = firstAbsoluteChild; currentAbsoluteChild != nil; currentAbsoluteChild = currentAbsoluteChild.NextChild {
-			mode := measureModeCrossDim
-			if isMainAxisRow {
-				mode = measureModeMainDim
-			}
+		isContainingBlock := nodeIsContainingBlock(node)
+		absoluteWidthMode := measureModeCrossDim
+		if isMainAxisRow {
+			absoluteWidthMode = measureModeMainDim
+		}
 
+		// STEP 10: SIZING AND POSITIONING ABSOLUTE CHILDREN
+		// Absolute children of a static node are laid out by its containing
+		// block in STEP 12.
+		for currentAbsoluteChild = firstAbsoluteChild; isContainingBlock && currentAbsoluteChild != nil; currentAbsoluteChild = currentAbsoluteChild.NextChild {
 			nodeAbsoluteLayoutChild(node,
 				currentAbsoluteChild,
 				availableInnerWidth,
-				mode,
+				absoluteWidthMode,
 				availableInnerHeight,
 				direction,
-				config)
+				config,
+				ctx)
 		}
 
 		// STEP 11: SETTING TRAILING POSITIONS FOR CHILDREN
@@ -2579,7 +3125,7 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 		// Set trailing position if necessary.
 		if needsMainTrailingPos || needsCrossTrailingPos {
 			for i := 0; i < childCount; i++ {
-				child := node.Children[i]
+				child := children[i]
 				if child.Style.Display == DisplayNone {
 					continue
 				}
@@ -2592,16 +3138,28 @@ func nodelayoutImpl(node *Node, availableWidth float32, availableHeight float32,
 				}
 			}
 		}
+
+		// STEP 12: SIZING AND POSITIONING ABSOLUTE DESCENDANTS OF STATIC CHILDREN
+		if isContainingBlock {
+			for _, child := range children {
+				if child.Style.Display == DisplayNone || child.Style.PositionType != PositionTypeStatic {
+					continue
+				}
+				nodeAbsoluteLayoutDescendants(node,
+					child,
+					child.Layout.Position[EdgeLeft],
+					child.Layout.Position[EdgeTop],
+					availableInnerWidth,
+					absoluteWidthMode,
+					availableInnerHeight,
+					direction,
+					config,
+					ctx)
+			}
+		}
 	}
 }
 
-var (
-	gDepth        = 0
-	gPrintTree    = false
-	gPrintChanges = false
-	gPrintSkips   = false
-)
-
 const (
 	spacerStr = "                                                            "
 )
@@ -2727,13 +3285,17 @@ func nodeCanUseCachedMeasurement(widthMode MeasureMode, width float32, heightMod
 func layoutNodeInternal(node *Node, availableWidth float32, availableHeight float32,
 	parentDirection Direction, widthMeasureMode MeasureMode,
 	heightMeasureMode MeasureMode, parentWidth float32, parentHeight float32,
-	performLayout bool, reason string, config *Config) bool {
+	performLayout bool, reason string, config *Config, ctx *layoutContext) bool {
 	layout := &node.Layout
 
-	gDepth++
+	ctx.depth++
+	ctx.stats.NodesVisited++
+	if ctx.depth > ctx.stats.MaxDepth {
+		ctx.stats.MaxDepth = ctx.depth
+	}
 
 	needToVisitNode :=
-		(node.IsDirty && layout.generationCount != currentGenerationCount) ||
+		(node.IsDirty && layout.generationCount != ctx.generationCount) ||
 			layout.lastParentDirection != parentDirection
 
 	if needToVisitNode {
@@ -2818,40 +3380,49 @@ func layoutNodeInternal(node *Node, availableWidth float32, availableHeight floa
 		}
 	}
 
+	tracer := config.Tracer
+	var event LayoutEvent
+	if tracer != nil {
+		event = LayoutEvent{
+			Node:              node,
+			Depth:             ctx.depth,
+			Reason:            reason,
+			PerformLayout:     performLayout,
+			NeedToVisit:       needToVisitNode,
+			AvailableWidth:    availableWidth,
+			AvailableHeight:   availableHeight,
+			WidthMeasureMode:  widthMeasureMode,
+			HeightMeasureMode: heightMeasureMode,
+			MeasuredWidth:     Undefined,
+			MeasuredHeight:    Undefined,
+		}
+	}
+
 	if !needToVisitNode && cachedResults != nil {
 		layout.measuredDimensions[DimensionWidth] = cachedResults.computedWidth
 		layout.measuredDimensions[DimensionHeight] = cachedResults.computedHeight
 
-		if gPrintChanges && gPrintSkips {
-			fmt.Printf("%s%d.{[skipped] ", spacer(gDepth), gDepth)
-			if node.Print != nil {
-				node.Print(node)
-			}
-			fmt.Printf("wm: %s, hm: %s, aw: %f ah: %f => d: (%f, %f) %s\n",
-				measureModeName(widthMeasureMode, performLayout),
-				measureModeName(heightMeasureMode, performLayout),
-				availableWidth,
-				availableHeight,
-				cachedResults.computedWidth,
-				cachedResults.computedHeight,
-				reason)
+		if cachedResults == &layout.cachedLayout {
+			ctx.stats.CachedLayoutHits++
+		} else {
+			ctx.stats.CachedMeasurementHits++
+		}
+
+		if tracer != nil {
+			event.Type = LayoutEventCacheHit
+			event.MeasuredWidth = cachedResults.computedWidth
+			event.MeasuredHeight = cachedResults.computedHeight
+			tracer.TraceLayout(event)
 		}
 	} else {
-		if gPrintChanges {
-			s := ""
-			if needToVisitNode {
-				s = "*"
-			}
-			fmt.Printf("%s%d.{%s", spacer(gDepth), gDepth, s)
-			if node.Print != nil {
-				node.Print(node)
-			}
-			fmt.Printf("wm: %s, hm: %s, aw: %f ah: %f %s\n",
-				measureModeName(widthMeasureMode, performLayout),
-				measureModeName(heightMeasureMode, performLayout),
-				availableWidth,
-				availableHeight,
-				reason)
+		if tracer != nil {
+			event.Type = LayoutEventEnter
+			tracer.TraceLayout(event)
+		}
+		if performLayout {
+			ctx.stats.Layouts++
+		} else {
+			ctx.stats.Measurements++
 		}
 
 		nodelayoutImpl(node,
@@ -2863,31 +3434,29 @@ func layoutNodeInternal(node *Node, availableWidth float32, availableHeight floa
 			parentWidth,
 			parentHeight,
 			performLayout,
-			config)
+			config,
+			ctx)
+		if performLayout {
+			nodeSetContentDimensions(node)
+		}
 
-		if gPrintChanges {
-			s := ""
-			if needToVisitNode {
-				s = "*"
-			}
-			fmt.Printf("%s%d.}%s", spacer(gDepth), gDepth, s)
-			if node.Print != nil {
-				node.Print(node)
-			}
-			fmt.Printf("wm: %s, hm: %s, d: (%f, %f) %s\n",
-				measureModeName(widthMeasureMode, performLayout),
-				measureModeName(heightMeasureMode, performLayout),
-				layout.measuredDimensions[DimensionWidth],
-				layout.measuredDimensions[DimensionHeight],
-				reason)
+		if tracer != nil {
+			event.Type = LayoutEventExit
+			event.MeasuredWidth = layout.measuredDimensions[DimensionWidth]
+			event.MeasuredHeight = layout.measuredDimensions[DimensionHeight]
+			tracer.TraceLayout(event)
 		}
 
 		layout.lastParentDirection = parentDirection
 
 		if cachedResults == nil {
 			if layout.nextCachedMeasurementsIndex == maxCachedResultCount {
-				if gPrintChanges {
-					fmt.Printf("Out of cache entries!\n")
+				ctx.stats.CacheOverflows++
+				if tracer != nil {
+					event.Type = LayoutEventCacheOverflow
+					event.MeasuredWidth = Undefined
+					event.MeasuredHeight = Undefined
+					tracer.TraceLayout(event)
 				}
 				layout.nextCachedMeasurementsIndex = 0
 			}
@@ -2914,18 +3483,28 @@ func layoutNodeInternal(node *Node, availableWidth float32, availableHeight floa
 	if performLayout {
 		node.Layout.Dimensions[DimensionWidth] = node.Layout.measuredDimensions[DimensionWidth]
 		node.Layout.Dimensions[DimensionHeight] = node.Layout.measuredDimensions[DimensionHeight]
-		node.hasNewLayout = true
+		nodeSetHasNewLayout(node)
 		node.IsDirty = false
 	}
 
-	gDepth--
-	layout.generationCount = currentGenerationCount
+	ctx.depth--
+	layout.generationCount = ctx.generationCount
 	return needToVisitNode || cachedResults == nil
 }
 
 // SetPointScaleFactor sets scale factor
 func (config *Config) SetPointScaleFactor(pixelsInPoint float32) {
-	assertWithConfig(config, pixelsInPoint >= 0, "Scale factor should not be less than zero")
+	if err := config.TrySetPointScaleFactor(pixelsInPoint); err != nil {
+		assertFailed(config, nil, err.Error())
+	}
+}
+
+// TrySetPointScaleFactor sets scale factor, returns an error instead of
+// panicking if it's negative
+func (config *Config) TrySetPointScaleFactor(pixelsInPoint float32) error {
+	if pixelsInPoint < 0 {
+		return ErrNegativePointScaleFactor
+	}
 
 	// We store points for Pixel as we will use it for rounding
 	if pixelsInPoint == 0 {
@@ -2934,6 +3513,7 @@ func (config *Config) SetPointScaleFactor(pixelsInPoint float32) {
 	} else {
 		config.PointScaleFactor = pixelsInPoint
 	}
+	return nil
 }
 
 func roundToPixelGrid(node *Node, pointScaleFactor float32, absoluteLeft float32, absoluteTop float32) {
@@ -2983,8 +3563,36 @@ func roundToPixelGrid(node *Node, pointScaleFactor float32, absoluteLeft float32
 			roundValueToPixelGrid(absoluteNodeTop, pointScaleFactor, false, textRounding)
 
 	for _, child := range node.Children {
+		// child shared with another tree wasn't laid out and is already
+		// rounded
+		if child.Parent != node {
+			continue
+		}
 		roundToPixelGrid(child, pointScaleFactor, absoluteNodeLeft, absoluteNodeTop)
 	}
+	nodeSetContentDimensions(node)
+}
+
+// nodeNotifyLayoutChanged calls onLayoutChanged for node and its descendants
+// whose frame changed since the previous call
+func nodeNotifyLayoutChanged(node *Node, onLayoutChanged func(node *Node)) {
+	frame := [4]float32{
+		node.Layout.Position[EdgeLeft],
+		node.Layout.Position[EdgeTop],
+		node.Layout.Dimensions[DimensionWidth],
+		node.Layout.Dimensions[DimensionHeight],
+	}
+	if frame != node.Layout.frame {
+		node.Layout.frame = frame
+		onLayoutChanged(node)
+	}
+	for _, child := range node.Children {
+		// child shared with another tree wasn't laid out
+		if child.Parent != node {
+			continue
+		}
+		nodeNotifyLayoutChanged(child, onLayoutChanged)
+	}
 }
 
 func calcStartWidth(node *Node, parentWidth float32) (float32, MeasureMode) {
@@ -3025,13 +3633,21 @@ func calcStartHeight(node *Node, parentWidth, parentHeight float32) (float32, Me
 
 // CalculateLayout calculates layout
 func CalculateLayout(node *Node, parentWidth float32, parentHeight float32, parentDirection Direction) {
-	// Increment the generation count. This will force the recursive routine to
-	// visit
-	// all dirty nodes at least once. Subsequent visits will be skipped if the
-	// input
-	// parameters don't change.
-	currentGenerationCount++
+	calculateLayout(node, parentWidth, parentHeight, parentDirection)
+}
 
+// CalculateLayoutWithStats calculates layout like CalculateLayout and returns
+// statistics about the work it did
+func CalculateLayoutWithStats(node *Node, parentWidth float32, parentHeight float32, parentDirection Direction) LayoutStats {
+	return calculateLayout(node, parentWidth, parentHeight, parentDirection).stats
+}
+
+func calculateLayout(node *Node, parentWidth float32, parentHeight float32, parentDirection Direction) *layoutContext {
+	ctx := newLayoutContext()
+
+	if node.Config.DetectStyleChanges {
+		nodeDetectStyleChanges(node, ctx)
+	}
 	resolveDimensions(node)
 
 	width, widthMeasureMode := calcStartWidth(node, parentWidth)
@@ -3039,14 +3655,29 @@ func CalculateLayout(node *Node, parentWidth float32, parentHeight float32, pare
 
 	if layoutNodeInternal(node, width, height, parentDirection,
 		widthMeasureMode, heightMeasureMode, parentWidth, parentHeight,
-		true, "initial", node.Config) {
+		true, "initial", node.Config, ctx) {
 		nodeSetPosition(node, node.Layout.Direction, parentWidth, parentHeight, parentWidth)
 		roundToPixelGrid(node, node.Config.PointScaleFactor, 0, 0)
+		if onLayoutChanged := node.Config.OnLayoutChanged; onLayoutChanged != nil {
+			nodeNotifyLayoutChanged(node, onLayoutChanged)
+		}
 
-		if gPrintTree {
-			NodePrint(node, PrintOptionsLayout|PrintOptionsChildren|PrintOptionsStyle)
+		if tracer := node.Config.Tracer; tracer != nil {
+			tracer.TraceLayout(LayoutEvent{
+				Type:              LayoutEventDone,
+				Node:              node,
+				Reason:            "initial",
+				PerformLayout:     true,
+				AvailableWidth:    width,
+				AvailableHeight:   height,
+				WidthMeasureMode:  widthMeasureMode,
+				HeightMeasureMode: heightMeasureMode,
+				MeasuredWidth:     node.Layout.Dimensions[DimensionWidth],
+				MeasuredHeight:    node.Layout.Dimensions[DimensionHeight],
+			})
 		}
 	}
+	return ctx
 }
 
 // SetExperimentalFeatureEnabled enables experimental feature
@@ -3060,21 +3691,49 @@ func (config *Config) IsExperimentalFeatureEnabled(feature ExperimentalFeature)
 }
 
 func log(node *Node, level LogLevel, format string, args ...interface{}) {
-	fmt.Printf(format, args...)
+	var config *Config
+	if node != nil {
+		config = node.Config
+	}
+	logWithConfig(config, node, level, format, args...)
+}
+
+func logWithConfig(config *Config, node *Node, level LogLevel, format string, args ...interface{}) {
+	if config == nil {
+		config = &configDefaults
+	}
+	logger := config.Logger
+	if logger == nil {
+		logger = DefaultLog
+	}
+	logger(config, node, level, format, args...)
+}
+
+// assertFailed logs msg as fatal and panics. If config.LogAssertions is set
+// it's logged as an error instead and the caller carries on.
+func assertFailed(config *Config, node *Node, msg string) {
+	if config != nil && config.LogAssertions {
+		logWithConfig(config, node, LogLevelError, "%s\n", msg)
+		return
+	}
+	logWithConfig(config, node, LogLevelFatal, "%s\n", msg)
+	panic(msg)
 }
 
 func assertCond(cond bool, format string, args ...interface{}) {
 	if !cond {
-		panic(format)
+		assertFailed(nil, nil, fmt.Sprintf(format, args...))
 	}
 }
 
 func assertWithNode(node *Node, cond bool, format string, args ...interface{}) {
-	assertCond(cond, format, args...)
+	if !cond {
+		assertFailed(node.Config, node, fmt.Sprintf(format, args...))
+	}
 }
 
 func assertWithConfig(config *Config, condition bool, message string) {
 	if !condition {
-		panic(message)
+		assertFailed(config, nil, message)
 	}
 }
@@ -30,6 +30,14 @@ type MeasureFunc func(node *Node, width float32, widthMode MeasureMode, height f
 // BaselineFunc describes function for baseline
 type BaselineFunc func(node *Node, width float32, height float32) float32
 
+// CloneNodeFunc defines function for cloning a node shared with another tree
+// before it's changed. It returns a clone of oldNode, usually made with Clone,
+// that becomes child of owner at childIndex
+type CloneNodeFunc func(oldNode *Node, owner *Node, childIndex int) *Node
+
+// DirtiedFunc defines function called when a clean node is marked dirty
+type DirtiedFunc func(node *Node)
+
 // PrintFunc defines function for printing
 type PrintFunc func(node *Node)
 
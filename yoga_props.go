@@ -245,6 +245,10 @@ func (node *Node) StyleSetDisplay(display Display) {
 	if node.Style.Display != display {
 		node.Style.Display = display
 		nodeMarkDirtyInternal(node)
+		// margins of block children collapse depending on display of node
+		for _, child := range node.Children {
+			nodeMarkDirtyInternal(child)
+		}
 	}
 }
 
@@ -396,6 +400,89 @@ func (node *Node) StyleGetBorder(edge Edge) float32 {
 	return node.Style.Border[edge].Value
 }
 
+// StyleSetGap sets gap
+func (node *Node) StyleSetGap(gutter Gutter, gap float32) {
+	if node.Style.Gap[gutter].Value != gap ||
+		node.Style.Gap[gutter].Unit != UnitPoint {
+		node.Style.Gap[gutter].Value = gap
+		node.Style.Gap[gutter].Unit = UnitPoint
+		if FloatIsUndefined(gap) {
+			node.Style.Gap[gutter].Unit = UnitUndefined
+		}
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleSetGapPercent sets gap percent
+func (node *Node) StyleSetGapPercent(gutter Gutter, gap float32) {
+	if node.Style.Gap[gutter].Value != gap ||
+		node.Style.Gap[gutter].Unit != UnitPercent {
+		node.Style.Gap[gutter].Value = gap
+		node.Style.Gap[gutter].Unit = UnitPercent
+		if FloatIsUndefined(gap) {
+			node.Style.Gap[gutter].Unit = UnitUndefined
+		}
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleGetGap gets gap
+func (node *Node) StyleGetGap(gutter Gutter) Value {
+	return node.Style.Gap[gutter]
+}
+
+// StyleSetGridTemplateColumns sets grid template columns
+func (node *Node) StyleSetGridTemplateColumns(tracks ...GridTrack) {
+	if !gridTracksEq(node.Style.GridTemplateColumns, tracks) {
+		node.Style.GridTemplateColumns = tracks
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleGetGridTemplateColumns gets grid template columns
+func (node *Node) StyleGetGridTemplateColumns() []GridTrack {
+	return node.Style.GridTemplateColumns
+}
+
+// StyleSetGridTemplateRows sets grid template rows
+func (node *Node) StyleSetGridTemplateRows(tracks ...GridTrack) {
+	if !gridTracksEq(node.Style.GridTemplateRows, tracks) {
+		node.Style.GridTemplateRows = tracks
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleGetGridTemplateRows gets grid template rows
+func (node *Node) StyleGetGridTemplateRows() []GridTrack {
+	return node.Style.GridTemplateRows
+}
+
+// StyleSetGridColumn sets grid column
+func (node *Node) StyleSetGridColumn(placement GridPlacement) {
+	if node.Style.GridColumn != placement {
+		node.Style.GridColumn = placement
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleGetGridColumn gets grid column
+func (node *Node) StyleGetGridColumn() GridPlacement {
+	return node.Style.GridColumn
+}
+
+// StyleSetGridRow sets grid row
+func (node *Node) StyleSetGridRow(placement GridPlacement) {
+	if node.Style.GridRow != placement {
+		node.Style.GridRow = placement
+		nodeMarkDirtyInternal(node)
+	}
+}
+
+// StyleGetGridRow gets grid row
+func (node *Node) StyleGetGridRow() GridPlacement {
+	return node.Style.GridRow
+}
+
 // StyleSetMinWidth sets min width
 func (node *Node) StyleSetMinWidth(minWidth float32) {
 	if node.Style.MinDimensions[DimensionWidth].Value != minWidth ||
@@ -558,9 +645,34 @@ func (node *Node) LayoutGetHeight() float32 {
 	return node.Layout.Dimensions[DimensionHeight]
 }
 
+// LayoutGetContentWidth gets width of content scrolled by a scroll container
+func (node *Node) LayoutGetContentWidth() float32 {
+	return node.Layout.ContentDimensions[DimensionWidth]
+}
+
+// LayoutGetContentHeight gets height of content scrolled by a scroll container
+func (node *Node) LayoutGetContentHeight() float32 {
+	return node.Layout.ContentDimensions[DimensionHeight]
+}
+
+// HasNewLayout returns true if node was laid out or positioned since the last
+// MarkLayoutSeen call. Descendants of a node without new layout don't have
+// new layout either
+func (node *Node) HasNewLayout() bool {
+	return node.hasNewLayout
+}
+
+// MarkLayoutSeen resets HasNewLayout after layout of node was read
+func (node *Node) MarkLayoutSeen() {
+	node.hasNewLayout = false
+}
+
 // LayoutGetMargin gets margin
 func (node *Node) LayoutGetMargin(edge Edge) float32 {
-	assertWithNode(node, edge < EdgeEnd, "Cannot get layout properties of multi-edge shorthands")
+	if edge >= EdgeEnd {
+		assertWithNode(node, false, "Cannot get layout properties of multi-edge shorthands")
+		return 0
+	}
 	if edge == EdgeLeft {
 		if node.Layout.Direction == DirectionRTL {
 			return node.Layout.Margin[EdgeEnd]
@@ -578,8 +690,10 @@ func (node *Node) LayoutGetMargin(edge Edge) float32 {
 
 // LayoutGetBorder gets border
 func (node *Node) LayoutGetBorder(edge Edge) float32 {
-	assertWithNode(node, edge < EdgeEnd,
-		"Cannot get layout properties of multi-edge shorthands")
+	if edge >= EdgeEnd {
+		assertWithNode(node, false, "Cannot get layout properties of multi-edge shorthands")
+		return 0
+	}
 	if edge == EdgeLeft {
 		if node.Layout.Direction == DirectionRTL {
 			return node.Layout.Border[EdgeEnd]
@@ -597,8 +711,10 @@ func (node *Node) LayoutGetBorder(edge Edge) float32 {
 
 // LayoutGetPadding gets padding
 func (node *Node) LayoutGetPadding(edge Edge) float32 {
-	assertWithNode(node, edge < EdgeEnd,
-		"Cannot get layout properties of multi-edge shorthands")
+	if edge >= EdgeEnd {
+		assertWithNode(node, false, "Cannot get layout properties of multi-edge shorthands")
+		return 0
+	}
 	if edge == EdgeLeft {
 		if node.Layout.Direction == DirectionRTL {
 			return node.Layout.Padding[EdgeEnd]